@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Hyaxia/blogwatcher/internal/activitypub"
+	"github.com/Hyaxia/blogwatcher/internal/storage"
+)
+
+var apBlogID int64
+
+var apCmd = &cobra.Command{
+	Use:   "ap",
+	Short: "Federate with the ActivityPub Fediverse",
+}
+
+var apFollowCmd = &cobra.Command{
+	Use:   "follow <actor>",
+	Short: "Follow a remote ActivityPub actor on behalf of one of your blogs",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := storage.OpenDatabase(dbPath)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		handler, err := activitypub.NewHandler(db, apBaseURL)
+		if err != nil {
+			return err
+		}
+
+		if err := handler.Follow(apBlogID, args[0]); err != nil {
+			return err
+		}
+
+		fmt.Printf("Sent Follow to %s\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	apFollowCmd.Flags().Int64Var(&apBlogID, "blog", 0, "local blog ID to follow on behalf of")
+	apFollowCmd.MarkFlagRequired("blog")
+
+	apCmd.AddCommand(apFollowCmd)
+	rootCmd.AddCommand(apCmd)
+}
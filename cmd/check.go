@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/Hyaxia/blogwatcher/internal/healthcheck"
+	"github.com/Hyaxia/blogwatcher/internal/storage"
+)
+
+var (
+	checkWorkers           int
+	checkFailureThreshold  int
+	checkIncludeArticles   bool
+	checkFixRedirects      bool
+	checkRedirectThreshold int
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Probe watched blogs (and their articles) for broken links and dead feeds",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := storage.OpenDatabase(dbPath)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		blogs, err := db.ListBlogs()
+		if err != nil {
+			return err
+		}
+
+		checker := healthcheck.NewChecker(db, checkWorkers)
+		checker.FailureThreshold = checkFailureThreshold
+		checker.IncludeArticles = checkIncludeArticles
+		checker.FixRedirects = checkFixRedirects
+		checker.RedirectThreshold = checkRedirectThreshold
+
+		results, err := checker.CheckBlogs(blogs)
+		if err != nil {
+			return err
+		}
+
+		printReport(results)
+		return nil
+	},
+}
+
+func printReport(results []healthcheck.Result) {
+	for _, r := range results {
+		status := fmt.Sprintf("%d", r.Check.Status)
+		if r.Check.Error != "" {
+			status = "unreachable"
+		}
+
+		line := fmt.Sprintf("%-30s %-12s %s", r.Blog.Name, status, r.Blog.URL)
+		switch {
+		case r.Disabled:
+			color.Red("%s  [disabled: %d consecutive failures]", line, r.Blog.ConsecutiveFailures)
+		case r.Blog.LastHealth == "failing":
+			color.Yellow("%s  [failing: %d consecutive]", line, r.Blog.ConsecutiveFailures)
+		default:
+			color.Green("%s", line)
+		}
+
+		if r.RedirectedTo != "" {
+			color.Cyan("  -> updated blog URL to %s", r.RedirectedTo)
+		}
+	}
+}
+
+func init() {
+	checkCmd.Flags().IntVar(&checkWorkers, "workers", 8, "number of concurrent link checks to run")
+	checkCmd.Flags().IntVar(&checkFailureThreshold, "failure-threshold", 5, "consecutive failures before a blog is disabled")
+	checkCmd.Flags().BoolVar(&checkIncludeArticles, "articles", false, "also check every known article URL, not just blog feed URLs")
+	checkCmd.Flags().BoolVar(&checkFixRedirects, "fix-redirects", false, "update a blog's URL once a permanent redirect is observed repeatedly")
+	checkCmd.Flags().IntVar(&checkRedirectThreshold, "redirect-threshold", 3, "consecutive permanent redirects before --fix-redirects updates the URL")
+	rootCmd.AddCommand(checkCmd)
+}
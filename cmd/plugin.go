@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Hyaxia/blogwatcher/internal/plugin"
+)
+
+var pluginsDir string
+
+var pluginCmd = &cobra.Command{
+	Use:   "plugin",
+	Short: "Manage blogwatcher plugins",
+}
+
+var pluginListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List declared plugins and whether they are enabled",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := plugin.LoadConfig(filepath.Join(pluginsDir, "plugins.toml"))
+		if err != nil {
+			return err
+		}
+
+		if len(cfg.Plugin) == 0 {
+			fmt.Println("No plugins declared.")
+			return nil
+		}
+
+		for _, decl := range cfg.Plugin {
+			status := "disabled"
+			if decl.Enabled {
+				status = "enabled"
+			}
+			fmt.Printf("%-20s %-8s %-10s %s\n", decl.Name, decl.Kind, decl.Type, status)
+		}
+		return nil
+	},
+}
+
+var pluginEnableCmd = &cobra.Command{
+	Use:   "enable <name>",
+	Short: "Enable a declared plugin",
+	Args:  cobra.ExactArgs(1),
+	RunE:  setPluginEnabled(true),
+}
+
+var pluginDisableCmd = &cobra.Command{
+	Use:   "disable <name>",
+	Short: "Disable a declared plugin",
+	Args:  cobra.ExactArgs(1),
+	RunE:  setPluginEnabled(false),
+}
+
+func setPluginEnabled(enabled bool) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		configPath := filepath.Join(pluginsDir, "plugins.toml")
+
+		cfg, err := plugin.LoadConfig(configPath)
+		if err != nil {
+			return err
+		}
+
+		name := args[0]
+		found := false
+		for i := range cfg.Plugin {
+			if cfg.Plugin[i].Name == name {
+				cfg.Plugin[i].Enabled = enabled
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("no plugin named %q declared in %s", name, configPath)
+		}
+
+		return plugin.SaveConfig(configPath, cfg)
+	}
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&pluginsDir, "plugins-dir", defaultPluginsDir(), "directory holding plugins.toml and exec plugin binaries")
+
+	pluginCmd.AddCommand(pluginListCmd, pluginEnableCmd, pluginDisableCmd)
+	rootCmd.AddCommand(pluginCmd)
+}
+
+func defaultPluginsDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "plugins"
+	}
+	return filepath.Join(home, ".config", "blogwatcher", "plugins")
+}
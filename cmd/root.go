@@ -0,0 +1,35 @@
+// Package cmd implements blogwatcher's command-line interface.
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+var dbPath string
+var apBaseURL string
+
+var rootCmd = &cobra.Command{
+	Use:   "blogwatcher",
+	Short: "Watch blogs for new articles",
+}
+
+// Execute runs the blogwatcher CLI.
+func Execute() error {
+	return rootCmd.Execute()
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&dbPath, "db", defaultDBPath(), "path to the blogwatcher SQLite database")
+	rootCmd.PersistentFlags().StringVar(&apBaseURL, "base-url", "http://localhost:8080", "public base URL this instance is reachable at, used for ActivityPub actor IDs")
+}
+
+func defaultDBPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "blogwatcher.db"
+	}
+	return filepath.Join(home, ".config", "blogwatcher", "blogwatcher.db")
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Hyaxia/blogwatcher/internal/activitypub"
+	"github.com/Hyaxia/blogwatcher/internal/fetch"
+	"github.com/Hyaxia/blogwatcher/internal/model"
+	"github.com/Hyaxia/blogwatcher/internal/plugin"
+	"github.com/Hyaxia/blogwatcher/internal/storage"
+)
+
+var scanCmd = &cobra.Command{
+	Use:   "scan",
+	Short: "Fetch every watched blog's feed and store new articles",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := storage.OpenDatabase(dbPath)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		registry, err := plugin.LoadRegistry(pluginsDir, db)
+		if err != nil {
+			return err
+		}
+		defer registry.Close()
+
+		apHandler, err := activitypub.NewHandler(db, apBaseURL)
+		if err != nil {
+			return err
+		}
+		dispatcher := activitypub.NewDispatcher(apHandler)
+
+		pipeline := fetch.NewPipeline(db, registry)
+		pipeline.Coordinator = storage.NewScanCoordinator(db, fmt.Sprintf("pid-%d", os.Getpid()))
+		pipeline.OnNewArticle = func(a model.Article) {
+			if err := dispatcher.EnqueueArticle(a.BlogID, a); err != nil {
+				fmt.Fprintf(cmd.OutOrStderr(), "enqueue federation delivery: %v\n", err)
+			}
+		}
+
+		added, err := pipeline.ScanAll()
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Added %d new article(s)\n", added)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(scanCmd)
+}
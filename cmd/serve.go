@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/spf13/cobra"
+
+	"github.com/Hyaxia/blogwatcher/internal/activitypub"
+	"github.com/Hyaxia/blogwatcher/internal/api"
+	"github.com/Hyaxia/blogwatcher/internal/model"
+	"github.com/Hyaxia/blogwatcher/internal/plugin"
+	"github.com/Hyaxia/blogwatcher/internal/storage"
+)
+
+var (
+	serveAddr           string
+	serveUsersFile      string
+	serveJWTSecret      string
+	serveAllowedOrigins []string
+)
+
+type usersConfig struct {
+	User []api.User `toml:"user"`
+}
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve the blog/article store over HTTP with JWT-guarded REST endpoints",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if serveJWTSecret == "" {
+			return fmt.Errorf("--jwt-secret (or BLOGWATCHER_JWT_SECRET) is required")
+		}
+
+		var users usersConfig
+		if _, err := toml.DecodeFile(serveUsersFile, &users); err != nil {
+			return fmt.Errorf("load users file %s: %w", serveUsersFile, err)
+		}
+
+		db, err := storage.OpenDatabase(dbPath)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		registry, err := plugin.LoadRegistry(pluginsDir, db)
+		if err != nil {
+			return err
+		}
+		defer registry.Close()
+
+		server := api.NewServer(db, registry, api.Config{
+			JWTSecret:      []byte(serveJWTSecret),
+			Users:          users.User,
+			AllowedOrigins: serveAllowedOrigins,
+		}, nil)
+
+		apHandler, err := activitypub.NewHandler(db, apBaseURL)
+		if err != nil {
+			return err
+		}
+		dispatcher := activitypub.NewDispatcher(apHandler)
+		go runDeliveryLoop(cmd.Context(), dispatcher)
+
+		server.OnNewArticle = func(a model.Article) {
+			if err := dispatcher.EnqueueArticle(a.BlogID, a); err != nil {
+				fmt.Fprintf(os.Stderr, "enqueue federation delivery: %v\n", err)
+			}
+		}
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/.well-known/webfinger", apHandler.Webfinger)
+		mux.HandleFunc("/blogs/", apBlogRoute(apHandler, server))
+		mux.Handle("/", server)
+
+		fmt.Printf("Listening on %s\n", serveAddr)
+		return http.ListenAndServe(serveAddr, mux)
+	},
+}
+
+// apBlogRoute dispatches /blogs/{id}/actor|outbox|inbox to the
+// ActivityPub handler and lets every other /blogs/... request (the REST
+// API's blog collection and by-ID routes) fall through to the API
+// server.
+func apBlogRoute(h *activitypub.Handler, fallback http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case hasSuffix(r.URL.Path, "/actor"):
+			h.Actor(w, r)
+		case hasSuffix(r.URL.Path, "/outbox"):
+			h.Outbox(w, r)
+		case hasSuffix(r.URL.Path, "/inbox"):
+			h.Inbox(w, r)
+		default:
+			fallback.ServeHTTP(w, r)
+		}
+	}
+}
+
+func hasSuffix(s, suffix string) bool {
+	return len(s) >= len(suffix) && s[len(s)-len(suffix):] == suffix
+}
+
+func runDeliveryLoop(ctx context.Context, dispatcher *activitypub.Dispatcher) {
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := dispatcher.DeliverDue(now); err != nil {
+				fmt.Fprintf(os.Stderr, "activitypub delivery: %v\n", err)
+			}
+		}
+	}
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveAddr, "addr", ":8080", "address to listen on")
+	serveCmd.Flags().StringVar(&serveUsersFile, "users-file", defaultUsersFile(), "path to the TOML user table")
+	serveCmd.Flags().StringVar(&serveJWTSecret, "jwt-secret", os.Getenv("BLOGWATCHER_JWT_SECRET"), "secret used to sign JWTs")
+	serveCmd.Flags().StringSliceVar(&serveAllowedOrigins, "cors-origin", nil, "origins allowed to make cross-origin requests (repeatable)")
+	rootCmd.AddCommand(serveCmd)
+}
+
+func defaultUsersFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "users.toml"
+	}
+	return filepath.Join(home, ".config", "blogwatcher", "users.toml")
+}
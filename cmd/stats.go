@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Hyaxia/blogwatcher/internal/storage"
+)
+
+var statsBlogID int64
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show post count, word count, and read time for a blog",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := storage.OpenDatabase(dbPath)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		stats, err := db.GetBlogStats(statsBlogID)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Posts:             %d\n", stats.PostCount)
+		fmt.Printf("Total words:       %d\n", stats.TotalWords)
+		fmt.Printf("Average read time: %.1f min\n", stats.AverageReadTime)
+		return nil
+	},
+}
+
+func init() {
+	statsCmd.Flags().Int64Var(&statsBlogID, "blog", 0, "blog ID to show stats for")
+	statsCmd.MarkFlagRequired("blog")
+	rootCmd.AddCommand(statsCmd)
+}
@@ -0,0 +1,78 @@
+package activitypub
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const acceptActivityJSON = "application/activity+json"
+
+// FetchActor retrieves and parses a remote actor document.
+func FetchActor(client *http.Client, iri string) (*Actor, error) {
+	req, err := http.NewRequest(http.MethodGet, iri, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build actor request: %w", err)
+	}
+	req.Header.Set("Accept", acceptActivityJSON)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch actor %s: %w", iri, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch actor %s: unexpected status %d", iri, resp.StatusCode)
+	}
+
+	var actor Actor
+	if err := json.NewDecoder(resp.Body).Decode(&actor); err != nil {
+		return nil, fmt.Errorf("decode actor %s: %w", iri, err)
+	}
+	return &actor, nil
+}
+
+// fetchActorPublicKey resolves a Signature header's keyId (an actor IRI
+// with a "#main-key"-style fragment) to the actor's Ed25519 public key.
+func fetchActorPublicKey(client *http.Client, keyID string) (ed25519.PublicKey, error) {
+	actorIRI, _, _ := strings.Cut(keyID, "#")
+
+	actor, err := FetchActor(client, actorIRI)
+	if err != nil {
+		return nil, err
+	}
+	if actor.PublicKey.PublicKeyPEM == "" {
+		return nil, fmt.Errorf("actor %s has no publicKeyPem", actorIRI)
+	}
+	return decodePublicKeyPEM(actor.PublicKey.PublicKeyPEM)
+}
+
+// postActivity signs body as keyID/priv and POSTs it to inbox.
+func postActivity(client *http.Client, inbox string, keyID string, priv ed25519.PrivateKey, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, inbox, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build inbox request: %w", err)
+	}
+	req.Header.Set("Content-Type", acceptActivityJSON)
+
+	if err := signRequest(req, keyID, priv); err != nil {
+		return fmt.Errorf("sign request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post to %s: %w", inbox, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("post to %s: unexpected status %d", inbox, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package activitypub
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/Hyaxia/blogwatcher/internal/model"
+	"github.com/Hyaxia/blogwatcher/internal/storage"
+)
+
+// maxDeliveryAttempts is how many attempts, the first one included, a
+// delivery gets before it is marked dead and no longer retried.
+const maxDeliveryAttempts = 5
+
+// Dispatcher delivers Create{Note} activities to followers' inboxes,
+// retrying failed deliveries with exponential backoff.
+type Dispatcher struct {
+	db      *storage.DB
+	baseURL string
+	client  *http.Client
+	priv    ed25519.PrivateKey
+}
+
+// NewDispatcher builds a Dispatcher sharing h's actor identity.
+func NewDispatcher(h *Handler) *Dispatcher {
+	return &Dispatcher{db: h.db, baseURL: h.baseURL, client: h.client, priv: h.priv}
+}
+
+// EnqueueArticle queues a delivery of article to every follower of
+// blogID.
+func (d *Dispatcher) EnqueueArticle(blogID int64, article model.Article) error {
+	followers, err := d.db.ListFollowers(blogID)
+	if err != nil {
+		return fmt.Errorf("list followers: %w", err)
+	}
+
+	for _, f := range followers {
+		if _, err := d.db.EnqueueDelivery(f.ID, article.ID); err != nil {
+			return fmt.Errorf("enqueue delivery to %s: %w", f.FollowerIRI, err)
+		}
+	}
+	return nil
+}
+
+// DeliverDue attempts every delivery whose next attempt is due,
+// recording success, failure-with-backoff, or permanent failure.
+func (d *Dispatcher) DeliverDue(now time.Time) error {
+	deliveries, err := d.db.ListDueDeliveries(now)
+	if err != nil {
+		return fmt.Errorf("list due deliveries: %w", err)
+	}
+
+	for _, delivery := range deliveries {
+		if err := d.attempt(delivery, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Dispatcher) attempt(delivery storage.Delivery, now time.Time) error {
+	article, err := d.db.GetArticle(delivery.ArticleID)
+	if err != nil {
+		return fmt.Errorf("load article %d: %w", delivery.ArticleID, err)
+	}
+	if article == nil {
+		return d.db.RecordDeliveryAttempt(delivery.ID, storage.DeliveryDead, delivery.Attempts, "article deleted", now)
+	}
+
+	followers, err := d.db.ListFollowers(article.BlogID)
+	if err != nil {
+		return fmt.Errorf("list followers: %w", err)
+	}
+
+	var inbox string
+	for _, f := range followers {
+		if f.ID == delivery.FollowerID {
+			inbox = f.Inbox
+			break
+		}
+	}
+	if inbox == "" {
+		return d.db.RecordDeliveryAttempt(delivery.ID, storage.DeliveryDead, delivery.Attempts, "follower no longer present", now)
+	}
+
+	handler := &Handler{baseURL: d.baseURL}
+	payload, err := json.Marshal(handler.createNoteFor(article.BlogID, *article))
+	if err != nil {
+		return fmt.Errorf("marshal activity: %w", err)
+	}
+
+	attempts := delivery.Attempts + 1
+	sendErr := postActivity(d.client, inbox, handler.keyID(article.BlogID), d.priv, payload)
+	if sendErr == nil {
+		return d.db.RecordDeliveryAttempt(delivery.ID, storage.DeliveryDelivered, attempts, "", now)
+	}
+
+	status := storage.DeliveryFailed
+	if attempts >= maxDeliveryAttempts {
+		status = storage.DeliveryDead
+	}
+	backoff := time.Duration(math.Pow(2, float64(attempts))) * time.Minute
+	return d.db.RecordDeliveryAttempt(delivery.ID, status, attempts, sendErr.Error(), now.Add(backoff))
+}
@@ -0,0 +1,45 @@
+package activitypub
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Follow sends a Follow activity from blogID's actor to a remote actor,
+// so that blog can also receive articles federated from elsewhere.
+// Acceptance arrives later as a Follow/Accept to our inbox and is not
+// waited for here.
+func (h *Handler) Follow(blogID int64, targetActorIRI string) error {
+	target, err := FetchActor(h.client, targetActorIRI)
+	if err != nil {
+		return fmt.Errorf("resolve target actor %s: %w", targetActorIRI, err)
+	}
+	if target.Inbox == "" {
+		return fmt.Errorf("actor %s has no inbox", targetActorIRI)
+	}
+
+	activity := Activity{
+		Context: contextActivityStreams,
+		ID:      fmt.Sprintf("%s#follow-%s", h.actorID(blogID), sanitizeForID(targetActorIRI)),
+		Type:    "Follow",
+		Actor:   h.actorID(blogID),
+		Object:  mustMarshal(targetActorIRI),
+	}
+
+	payload, err := json.Marshal(activity)
+	if err != nil {
+		return fmt.Errorf("marshal follow: %w", err)
+	}
+
+	return postActivity(h.client, target.Inbox, h.keyID(blogID), h.priv, payload)
+}
+
+func sanitizeForID(iri string) string {
+	b := []byte(iri)
+	for i, c := range b {
+		if c == '/' || c == ':' {
+			b[i] = '-'
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,330 @@
+package activitypub
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Hyaxia/blogwatcher/internal/model"
+	"github.com/Hyaxia/blogwatcher/internal/storage"
+)
+
+// Handler serves the ActivityPub actor, outbox, and inbox for every
+// watched blog, and the webfinger endpoint used to discover them. Each
+// blog is its own actor; all of them share this instance's Ed25519
+// keypair.
+type Handler struct {
+	db      *storage.DB
+	baseURL string
+	client  *http.Client
+	priv    ed25519.PrivateKey
+	pub     ed25519.PublicKey
+}
+
+// NewHandler builds a Handler for blogs served from baseURL (e.g.
+// "https://blogwatcher.example.com"), generating this instance's actor
+// keypair on first use.
+func NewHandler(db *storage.DB, baseURL string) (*Handler, error) {
+	priv, pub, err := db.GetOrCreateActorKeypair()
+	if err != nil {
+		return nil, fmt.Errorf("load actor keypair: %w", err)
+	}
+	return &Handler{
+		db:      db,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  http.DefaultClient,
+		priv:    priv,
+		pub:     pub,
+	}, nil
+}
+
+func (h *Handler) actorID(blogID int64) string {
+	return fmt.Sprintf("%s/blogs/%d/actor", h.baseURL, blogID)
+}
+func (h *Handler) inboxID(blogID int64) string {
+	return fmt.Sprintf("%s/blogs/%d/inbox", h.baseURL, blogID)
+}
+func (h *Handler) outboxID(blogID int64) string {
+	return fmt.Sprintf("%s/blogs/%d/outbox", h.baseURL, blogID)
+}
+func (h *Handler) keyID(blogID int64) string { return h.actorID(blogID) + "#main-key" }
+
+// Webfinger resolves acct:blog-{id}@host to that blog's actor.
+func (h *Handler) Webfinger(w http.ResponseWriter, r *http.Request) {
+	blogID, ok := blogIDFromAcct(r.URL.Query().Get("resource"))
+	if !ok {
+		http.Error(w, "unknown resource", http.StatusNotFound)
+		return
+	}
+
+	if _, err := h.requireBlog(w, blogID); err != nil {
+		return
+	}
+
+	writeJSON(w, "application/jrd+json", map[string]any{
+		"subject": r.URL.Query().Get("resource"),
+		"links": []map[string]string{{
+			"rel":  "self",
+			"type": acceptActivityJSON,
+			"href": h.actorID(blogID),
+		}},
+	})
+}
+
+// Actor serves the Person document for /blogs/{id}/actor.
+func (h *Handler) Actor(w http.ResponseWriter, r *http.Request) {
+	blogID, err := blogIDFromPath(r.URL.Path, "/actor")
+	if err != nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	blog, err := h.requireBlog(w, blogID)
+	if err != nil {
+		return
+	}
+
+	pubPEM, err := encodePublicKeyPEM(h.pub)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, acceptActivityJSON, Actor{
+		Context:           contextActivityStreams,
+		ID:                h.actorID(blogID),
+		Type:              "Person",
+		PreferredUsername: fmt.Sprintf("blog-%d", blogID),
+		Name:              blog.Name,
+		Inbox:             h.inboxID(blogID),
+		Outbox:            h.outboxID(blogID),
+		PublicKey: PublicKey{
+			ID:           h.keyID(blogID),
+			Owner:        h.actorID(blogID),
+			PublicKeyPEM: pubPEM,
+		},
+	})
+}
+
+// Outbox serves an OrderedCollection of Create{Note} activities, one per
+// article collected for the blog.
+func (h *Handler) Outbox(w http.ResponseWriter, r *http.Request) {
+	blogID, err := blogIDFromPath(r.URL.Path, "/outbox")
+	if err != nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	if _, err := h.requireBlog(w, blogID); err != nil {
+		return
+	}
+
+	articles, err := h.db.ListArticles(false, &blogID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	items := make([]CreateNote, len(articles))
+	for i, a := range articles {
+		items[i] = h.createNoteFor(blogID, a)
+	}
+
+	writeJSON(w, acceptActivityJSON, OrderedCollection{
+		Context:      contextActivityStreams,
+		ID:           h.outboxID(blogID),
+		Type:         "OrderedCollection",
+		TotalItems:   len(items),
+		OrderedItems: items,
+	})
+}
+
+func (h *Handler) createNoteFor(blogID int64, a model.Article) CreateNote {
+	published := formatTime(a.CreatedAt)
+	return CreateNote{
+		Context:   contextActivityStreams,
+		ID:        fmt.Sprintf("%s/blogs/%d/articles/%d", h.baseURL, blogID, a.ID),
+		Type:      "Create",
+		Actor:     h.actorID(blogID),
+		Published: published,
+		To:        []string{"https://www.w3.org/ns/activitystreams#Public"},
+		Object: Note{
+			ID:           fmt.Sprintf("%s/blogs/%d/articles/%d", h.baseURL, blogID, a.ID),
+			Type:         "Note",
+			AttributedTo: h.actorID(blogID),
+			Content:      a.Title,
+			URL:          a.URL,
+			Published:    published,
+		},
+	}
+}
+
+// Inbox accepts Follow, Undo{Follow}, and Create{Note|Article} activities
+// addressed to /blogs/{id}/inbox.
+func (h *Handler) Inbox(w http.ResponseWriter, r *http.Request) {
+	blogID, err := blogIDFromPath(r.URL.Path, "/inbox")
+	if err != nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	if _, err := h.requireBlog(w, blogID); err != nil {
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "read body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.verifyInbound(r, body); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var activity Activity
+	if err := json.Unmarshal(body, &activity); err != nil {
+		http.Error(w, "invalid activity", http.StatusBadRequest)
+		return
+	}
+
+	switch activity.Type {
+	case "Follow":
+		err = h.handleFollow(blogID, activity)
+	case "Undo":
+		err = h.handleUndo(blogID, activity)
+	case "Create":
+		err = h.handleCreate(blogID, activity)
+	default:
+		// Unrecognized activity types are accepted and ignored, per the
+		// ActivityPub spec's recommendation for forward compatibility.
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (h *Handler) verifyInbound(r *http.Request, body []byte) error {
+	header := r.Header.Get("Signature")
+	if header == "" {
+		return fmt.Errorf("missing Signature header")
+	}
+	params := parseSignatureHeader(header)
+	keyID, ok := params["keyId"]
+	if !ok {
+		return fmt.Errorf("signature missing keyId")
+	}
+
+	pub, err := fetchActorPublicKey(h.client, keyID)
+	if err != nil {
+		return fmt.Errorf("resolve signer: %w", err)
+	}
+
+	return verifyRequest(r, body, pub)
+}
+
+func (h *Handler) handleFollow(blogID int64, activity Activity) error {
+	follower, err := FetchActor(h.client, activity.Actor)
+	if err != nil {
+		return fmt.Errorf("resolve follower %s: %w", activity.Actor, err)
+	}
+
+	if _, err := h.db.AddFollower(blogID, activity.Actor, follower.Inbox); err != nil {
+		return fmt.Errorf("store follower: %w", err)
+	}
+
+	accept, err := json.Marshal(Activity{
+		Context: contextActivityStreams,
+		ID:      fmt.Sprintf("%s#accept-%s", h.actorID(blogID), activity.ID),
+		Type:    "Accept",
+		Actor:   h.actorID(blogID),
+		Object:  mustMarshal(activity),
+	})
+	if err != nil {
+		return fmt.Errorf("build accept: %w", err)
+	}
+
+	return postActivity(h.client, follower.Inbox, h.keyID(blogID), h.priv, accept)
+}
+
+func (h *Handler) handleUndo(blogID int64, activity Activity) error {
+	_, err := h.db.RemoveFollower(blogID, activity.Actor)
+	if err != nil {
+		return fmt.Errorf("remove follower: %w", err)
+	}
+	return nil
+}
+
+func (h *Handler) handleCreate(blogID int64, activity Activity) error {
+	var note Note
+	if err := json.Unmarshal(activity.Object, &note); err != nil {
+		return fmt.Errorf("decode created object: %w", err)
+	}
+
+	title := note.Content
+	if title == "" {
+		title = "Untitled"
+	}
+
+	_, err := h.db.AddArticle(model.Article{
+		BlogID:  blogID,
+		Title:   title,
+		URL:     note.URL,
+		Content: note.Content,
+	})
+	if err != nil {
+		return fmt.Errorf("store federated article: %w", err)
+	}
+	return nil
+}
+
+func (h *Handler) requireBlog(w http.ResponseWriter, blogID int64) (*model.Blog, error) {
+	blog, err := h.db.GetBlog(blogID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return nil, err
+	}
+	if blog == nil {
+		http.Error(w, "blog not found", http.StatusNotFound)
+		return nil, fmt.Errorf("blog not found")
+	}
+	return blog, nil
+}
+
+func blogIDFromAcct(resource string) (int64, bool) {
+	resource = strings.TrimPrefix(resource, "acct:")
+	user, _, ok := strings.Cut(resource, "@")
+	if !ok || !strings.HasPrefix(user, "blog-") {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(user, "blog-"), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
+
+func blogIDFromPath(path, suffix string) (int64, error) {
+	path = strings.TrimSuffix(path, suffix)
+	path = strings.TrimPrefix(path, "/blogs/")
+	return strconv.ParseInt(path, 10, 64)
+}
+
+func writeJSON(w http.ResponseWriter, contentType string, v any) {
+	w.Header().Set("Content-Type", contentType)
+	json.NewEncoder(w).Encode(v)
+}
+
+func mustMarshal(v any) []byte {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return b
+}
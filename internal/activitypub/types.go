@@ -0,0 +1,80 @@
+// Package activitypub turns blogwatcher's collected articles into a set
+// of Fediverse actors (one per blog) that can be followed, and lets
+// blogwatcher itself follow remote actors that federate their own posts.
+package activitypub
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const contextActivityStreams = "https://www.w3.org/ns/activitystreams"
+
+// Actor is the ActivityPub Person representing a single watched blog.
+type Actor struct {
+	Context           any       `json:"@context"`
+	ID                string    `json:"id"`
+	Type              string    `json:"type"`
+	PreferredUsername string    `json:"preferredUsername"`
+	Name              string    `json:"name"`
+	Inbox             string    `json:"inbox"`
+	Outbox            string    `json:"outbox"`
+	PublicKey         PublicKey `json:"publicKey"`
+}
+
+// PublicKey is the PEM-encoded public key an Actor publishes so remote
+// servers can verify our HTTP Signatures.
+type PublicKey struct {
+	ID           string `json:"id"`
+	Owner        string `json:"owner"`
+	PublicKeyPEM string `json:"publicKeyPem"`
+}
+
+// OrderedCollection is the outbox: every article we've published, newest
+// first.
+type OrderedCollection struct {
+	Context      string       `json:"@context"`
+	ID           string       `json:"id"`
+	Type         string       `json:"type"`
+	TotalItems   int          `json:"totalItems"`
+	OrderedItems []CreateNote `json:"orderedItems"`
+}
+
+// CreateNote wraps an article in a Create{Note} activity, the standard
+// shape for "I published this" on the Fediverse.
+type CreateNote struct {
+	Context   string   `json:"@context"`
+	ID        string   `json:"id"`
+	Type      string   `json:"type"`
+	Actor     string   `json:"actor"`
+	Published string   `json:"published"`
+	To        []string `json:"to"`
+	Object    Note     `json:"object"`
+}
+
+// Note is an article rendered as ActivityStreams content.
+type Note struct {
+	ID           string `json:"id"`
+	Type         string `json:"type"`
+	AttributedTo string `json:"attributedTo"`
+	Content      string `json:"content"`
+	URL          string `json:"url"`
+	Published    string `json:"published"`
+}
+
+// Activity is the generic envelope used to decode inbound Follow/Undo/
+// Create activities, whose shape we don't fully know ahead of time.
+type Activity struct {
+	Context any             `json:"@context"`
+	ID      string          `json:"id"`
+	Type    string          `json:"type"`
+	Actor   string          `json:"actor"`
+	Object  json.RawMessage `json:"object"`
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	return t.UTC().Format(time.RFC3339)
+}
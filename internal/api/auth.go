@@ -0,0 +1,122 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Scope is a permission a token can carry. Routes declare the scope they
+// require; a token must carry that scope (or admin) to be let through.
+type Scope string
+
+const (
+	ScopeRead  Scope = "read"
+	ScopeWrite Scope = "write"
+	ScopeAdmin Scope = "admin"
+)
+
+// User is one entry in the server's config-file user table.
+type User struct {
+	Username     string  `toml:"username"`
+	PasswordHash string  `toml:"password_hash"`
+	Scopes       []Scope `toml:"scopes"`
+}
+
+// claims is the JWT payload issued by POST /token.
+type claims struct {
+	Scopes []Scope `json:"scopes"`
+	jwt.RegisteredClaims
+}
+
+type contextKey string
+
+const userContextKey contextKey = "user"
+
+// authUser is the identity attached to a request's context once its
+// token has been validated.
+type authUser struct {
+	Username string
+	Scopes   []Scope
+}
+
+const tokenTTL = 24 * time.Hour
+
+// issueToken authenticates username/password against the server's user
+// table and, on success, returns a signed HS256 JWT carrying that user's
+// scopes.
+func (s *Server) issueToken(username, password string) (string, error) {
+	for _, u := range s.cfg.Users {
+		if u.Username != username {
+			continue
+		}
+		if err := bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)); err != nil {
+			return "", errors.New("invalid credentials")
+		}
+
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims{
+			Scopes: u.Scopes,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   u.Username,
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(tokenTTL)),
+				IssuedAt:  jwt.NewNumericDate(time.Now()),
+			},
+		})
+		return token.SignedString(s.cfg.JWTSecret)
+	}
+	return "", errors.New("invalid credentials")
+}
+
+// requireScope wraps next so that it only runs once the request's
+// Authorization header carries a valid token with the given scope (or
+// admin).
+func (s *Server) requireScope(scope Scope, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := s.authenticate(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if !hasScope(user.Scopes, scope) {
+			http.Error(w, "insufficient scope", http.StatusForbidden)
+			return
+		}
+		next(w, r.WithContext(context.WithValue(r.Context(), userContextKey, user)))
+	}
+}
+
+func (s *Server) authenticate(r *http.Request) (authUser, error) {
+	header := r.Header.Get("Authorization")
+	tokenStr, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok || tokenStr == "" {
+		return authUser{}, errors.New("missing bearer token")
+	}
+
+	parsed, err := jwt.ParseWithClaims(tokenStr, &claims{}, func(t *jwt.Token) (any, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method %v", t.Header["alg"])
+		}
+		return s.cfg.JWTSecret, nil
+	})
+	if err != nil || !parsed.Valid {
+		return authUser{}, errors.New("invalid token")
+	}
+
+	c := parsed.Claims.(*claims)
+	return authUser{Username: c.Subject, Scopes: c.Scopes}, nil
+}
+
+func hasScope(scopes []Scope, want Scope) bool {
+	for _, s := range scopes {
+		if s == want || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
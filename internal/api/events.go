@@ -0,0 +1,80 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/Hyaxia/blogwatcher/internal/model"
+)
+
+// eventBroker fans out newly discovered articles to every connected SSE
+// client.
+type eventBroker struct {
+	mu          sync.Mutex
+	subscribers map[chan model.Article]struct{}
+}
+
+func newEventBroker() *eventBroker {
+	return &eventBroker{subscribers: make(map[chan model.Article]struct{})}
+}
+
+func (b *eventBroker) subscribe() chan model.Article {
+	ch := make(chan model.Article, 16)
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+	return ch
+}
+
+func (b *eventBroker) unsubscribe(ch chan model.Article) {
+	b.mu.Lock()
+	delete(b.subscribers, ch)
+	b.mu.Unlock()
+	close(ch)
+}
+
+func (b *eventBroker) publish(a model.Article) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subscribers {
+		select {
+		case ch <- a:
+		default:
+			// Slow subscriber; drop the event rather than block the scan.
+		}
+	}
+}
+
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	ch := s.broker.subscribe()
+	defer s.broker.unsubscribe(ch)
+
+	for {
+		select {
+		case article, ok := <-ch:
+			if !ok {
+				return
+			}
+			payload, err := json.Marshal(article)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: article\ndata: %s\n\n", payload)
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Hyaxia/blogwatcher/internal/fetch"
+	"github.com/Hyaxia/blogwatcher/internal/model"
+)
+
+type tokenRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type tokenResponse struct {
+	Token string `json:"token"`
+}
+
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req tokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	token, err := s.issueToken(req.Username, req.Password)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, tokenResponse{Token: token})
+}
+
+func (s *Server) handleBlogsCollection(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		blogs, err := s.db.ListBlogs()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusOK, blogs)
+
+	case http.MethodPost:
+		if !hasScope(currentUser(r).Scopes, ScopeWrite) {
+			http.Error(w, "insufficient scope", http.StatusForbidden)
+			return
+		}
+		var blog model.Blog
+		if err := json.NewDecoder(r.Body).Decode(&blog); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		created, err := s.db.AddBlog(blog)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, http.StatusCreated, created)
+
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleBlogByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := pathID(r.URL.Path, "/blogs/")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	deleted, err := s.db.RemoveBlog(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !deleted {
+		http.Error(w, "blog not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleArticles(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	unread := r.URL.Query().Get("unread") == "true"
+
+	var blogID *int64
+	if raw := r.URL.Query().Get("blog"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			http.Error(w, "invalid blog query parameter", http.StatusBadRequest)
+			return
+		}
+		blogID = &id
+	}
+
+	articles, err := s.db.ListArticles(unread, blogID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, articles)
+}
+
+func (s *Server) handleArticleRead(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || !strings.HasSuffix(r.URL.Path, "/read") {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	id, err := pathID(strings.TrimSuffix(r.URL.Path, "/read"), "/articles/")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ok, err := s.db.MarkArticleRead(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		http.Error(w, "article not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleScan(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	pipeline := fetch.NewPipeline(s.db, s.registry)
+	pipeline.Coordinator = s.coordinator
+	pipeline.OnNewArticle = func(a model.Article) {
+		s.broker.publish(a)
+		if s.OnNewArticle != nil {
+			s.OnNewArticle(a)
+		}
+	}
+
+	added, err := pipeline.ScanAll()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]int{"added": added})
+}
+
+func currentUser(r *http.Request) authUser {
+	u, _ := r.Context().Value(userContextKey).(authUser)
+	return u
+}
+
+func pathID(path, prefix string) (int64, error) {
+	raw := strings.TrimPrefix(path, prefix)
+	return strconv.ParseInt(raw, 10, 64)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
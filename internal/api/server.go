@@ -0,0 +1,111 @@
+// Package api exposes blogwatcher's SQLite-backed store over HTTP: blogs,
+// articles, on-demand scans, and a server-sent-events stream of newly
+// discovered articles.
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/Hyaxia/blogwatcher/internal/model"
+	"github.com/Hyaxia/blogwatcher/internal/plugin"
+	"github.com/Hyaxia/blogwatcher/internal/storage"
+)
+
+// Config configures a Server.
+type Config struct {
+	JWTSecret      []byte
+	Users          []User
+	AllowedOrigins []string
+}
+
+// Server exposes db over HTTP, guarding every route but /token with the
+// JWT scopes in Config.Users.
+type Server struct {
+	db          *storage.DB
+	registry    *plugin.Registry
+	cfg         Config
+	logger      *slog.Logger
+	broker      *eventBroker
+	coordinator *storage.ScanCoordinator
+	handler     http.Handler
+
+	// OnNewArticle, if set, is called (in addition to publishing to the
+	// SSE broker) for every article a /scan request stores. serve uses
+	// this to enqueue ActivityPub deliveries without the api package
+	// needing to know about federation.
+	OnNewArticle func(model.Article)
+}
+
+// NewServer builds a Server ready to be passed to http.ListenAndServe.
+// registry may be nil, in which case scans run with no plugins.
+func NewServer(db *storage.DB, registry *plugin.Registry, cfg Config, logger *slog.Logger) *Server {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	s := &Server{
+		db:          db,
+		registry:    registry,
+		cfg:         cfg,
+		logger:      logger,
+		broker:      newEventBroker(),
+		coordinator: storage.NewScanCoordinator(db, "api-server"),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", s.handleToken)
+	mux.HandleFunc("/blogs", s.requireScope(ScopeRead, s.handleBlogsCollection))
+	mux.HandleFunc("/blogs/", s.requireScope(ScopeWrite, s.handleBlogByID))
+	mux.HandleFunc("/articles", s.requireScope(ScopeRead, s.handleArticles))
+	mux.HandleFunc("/articles/", s.requireScope(ScopeWrite, s.handleArticleRead))
+	mux.HandleFunc("/scan", s.requireScope(ScopeWrite, s.handleScan))
+	mux.HandleFunc("/events", s.requireScope(ScopeRead, s.handleEvents))
+
+	s.handler = s.withCORS(s.withLogging(mux))
+
+	return s
+}
+
+// ServeHTTP lets a Server be used directly as an http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.handler.ServeHTTP(w, r)
+}
+
+func (s *Server) withLogging(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		next.ServeHTTP(w, r)
+		s.logger.Info("request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"duration", time.Since(start),
+		)
+	})
+}
+
+func (s *Server) withCORS(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin != "" && originAllowed(origin, s.cfg.AllowedOrigins) {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+		}
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func originAllowed(origin string, allowed []string) bool {
+	for _, a := range allowed {
+		if a == "*" || a == origin {
+			return true
+		}
+	}
+	return false
+}
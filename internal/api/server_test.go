@@ -0,0 +1,173 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/Hyaxia/blogwatcher/internal/model"
+	"github.com/Hyaxia/blogwatcher/internal/storage"
+)
+
+func newTestServer(t *testing.T) (*Server, *storage.DB) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "blogwatcher.db")
+	db, err := storage.OpenDatabase(path)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("s3cret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("hash password: %v", err)
+	}
+
+	server := NewServer(db, nil, Config{
+		JWTSecret: []byte("test-secret"),
+		Users: []User{
+			{Username: "alice", PasswordHash: string(hash), Scopes: []Scope{ScopeRead, ScopeWrite}},
+		},
+	}, nil)
+
+	return server, db
+}
+
+func authToken(t *testing.T, server *Server) string {
+	t.Helper()
+
+	rr := httptest.NewRecorder()
+	body := strings.NewReader(`{"username":"alice","password":"s3cret"}`)
+	req := httptest.NewRequest(http.MethodPost, "/token", body)
+	server.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("token request: expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var resp tokenResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode token response: %v", err)
+	}
+	return resp.Token
+}
+
+func TestTokenRejectsBadCredentials(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/token", strings.NewReader(`{"username":"alice","password":"wrong"}`))
+	server.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", rr.Code)
+	}
+}
+
+func TestBlogsRequiresAuth(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/blogs", nil)
+	server.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without a token, got %d", rr.Code)
+	}
+}
+
+func TestCreateListAndDeleteBlog(t *testing.T) {
+	server, _ := newTestServer(t)
+	token := authToken(t, server)
+
+	createBody := strings.NewReader(`{"name":"Test Blog","url":"https://example.com/feed"}`)
+	createReq := httptest.NewRequest(http.MethodPost, "/blogs", createBody)
+	createReq.Header.Set("Authorization", "Bearer "+token)
+	createRR := httptest.NewRecorder()
+	server.ServeHTTP(createRR, createReq)
+
+	if createRR.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", createRR.Code, createRR.Body.String())
+	}
+
+	var blog model.Blog
+	if err := json.Unmarshal(createRR.Body.Bytes(), &blog); err != nil {
+		t.Fatalf("decode created blog: %v", err)
+	}
+	if blog.ID == 0 {
+		t.Fatal("expected blog ID")
+	}
+
+	listReq := httptest.NewRequest(http.MethodGet, "/blogs", nil)
+	listReq.Header.Set("Authorization", "Bearer "+token)
+	listRR := httptest.NewRecorder()
+	server.ServeHTTP(listRR, listReq)
+
+	var blogs []model.Blog
+	if err := json.Unmarshal(listRR.Body.Bytes(), &blogs); err != nil {
+		t.Fatalf("decode blog list: %v", err)
+	}
+	if len(blogs) != 1 {
+		t.Fatalf("expected 1 blog, got %d", len(blogs))
+	}
+
+	deleteReq := httptest.NewRequest(http.MethodDelete, "/blogs/"+strconv.FormatInt(blog.ID, 10), nil)
+	deleteReq.Header.Set("Authorization", "Bearer "+token)
+	deleteRR := httptest.NewRecorder()
+	server.ServeHTTP(deleteRR, deleteReq)
+
+	if deleteRR.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", deleteRR.Code)
+	}
+}
+
+func TestArticlesAndMarkRead(t *testing.T) {
+	server, db := newTestServer(t)
+	token := authToken(t, server)
+
+	blog, err := db.AddBlog(model.Blog{Name: "Test", URL: "https://example.com"})
+	if err != nil {
+		t.Fatalf("add blog: %v", err)
+	}
+	article, err := db.AddArticle(model.Article{BlogID: blog.ID, Title: "One", URL: "https://example.com/1"})
+	if err != nil {
+		t.Fatalf("add article: %v", err)
+	}
+
+	listReq := httptest.NewRequest(http.MethodGet, "/articles?unread=true", nil)
+	listReq.Header.Set("Authorization", "Bearer "+token)
+	listRR := httptest.NewRecorder()
+	server.ServeHTTP(listRR, listReq)
+
+	var articles []model.Article
+	if err := json.Unmarshal(listRR.Body.Bytes(), &articles); err != nil {
+		t.Fatalf("decode article list: %v", err)
+	}
+	if len(articles) != 1 {
+		t.Fatalf("expected 1 unread article, got %d", len(articles))
+	}
+
+	readReq := httptest.NewRequest(http.MethodPost, "/articles/"+strconv.FormatInt(article.ID, 10)+"/read", nil)
+	readReq.Header.Set("Authorization", "Bearer "+token)
+	readRR := httptest.NewRecorder()
+	server.ServeHTTP(readRR, readReq)
+
+	if readRR.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", readRR.Code, readRR.Body.String())
+	}
+
+	updated, err := db.GetArticle(article.ID)
+	if err != nil {
+		t.Fatalf("get article: %v", err)
+	}
+	if !updated.IsRead {
+		t.Fatal("expected article to be marked read")
+	}
+}
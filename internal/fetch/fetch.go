@@ -0,0 +1,148 @@
+// Package fetch implements blogwatcher's scan pipeline: pulling a blog's
+// feed, filtering out articles already known to the database, and
+// storing the rest.
+package fetch
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+
+	"github.com/Hyaxia/blogwatcher/internal/model"
+	"github.com/Hyaxia/blogwatcher/internal/plugin"
+	"github.com/Hyaxia/blogwatcher/internal/storage"
+)
+
+// Pipeline scans blogs for new articles and stores them, giving any
+// registered plugins a chance to extend, filter, or react to the results.
+type Pipeline struct {
+	db       *storage.DB
+	parser   *gofeed.Parser
+	registry *plugin.Registry
+
+	// OnNewArticle, if set, is called for every article stored during a
+	// scan, after notifier plugins have run. Callers such as the API
+	// server's SSE stream use this to observe new articles without
+	// polling the database.
+	OnNewArticle func(model.Article)
+
+	// Coordinator, if set, is used to ensure only one scan of a given
+	// blog runs at a time; ScanBlog skips a blog outright if it can't
+	// claim the lock rather than blocking.
+	Coordinator *storage.ScanCoordinator
+}
+
+// NewPipeline returns a scan pipeline backed by db. registry may be nil,
+// in which case no plugins run.
+func NewPipeline(db *storage.DB, registry *plugin.Registry) *Pipeline {
+	return &Pipeline{db: db, parser: gofeed.NewParser(), registry: registry}
+}
+
+// ScanBlog fetches a single blog's feed, merges in anything reported by
+// fetcher plugins, drops articles already stored or rejected by filter
+// plugins, stores what remains, and notifies notifier plugins about each
+// one. It returns how many articles were added. If a Coordinator is set
+// and another scan of this blog is already in flight, ScanBlog returns
+// immediately with (0, nil) rather than waiting.
+func (p *Pipeline) ScanBlog(blog model.Blog) (int, error) {
+	if p.Coordinator != nil {
+		release, ok, err := p.Coordinator.TryAcquire(blog.ID)
+		if err != nil {
+			return 0, fmt.Errorf("acquire scan lock for %q: %w", blog.URL, err)
+		}
+		if !ok {
+			return 0, nil
+		}
+		defer release()
+	}
+
+	feed, err := p.parser.ParseURL(blog.URL)
+	if err != nil {
+		return 0, fmt.Errorf("parse feed for %q: %w", blog.URL, err)
+	}
+
+	candidates := make([]model.Article, 0, len(feed.Items))
+	for _, item := range feed.Items {
+		candidates = append(candidates, model.Article{
+			BlogID:  blog.ID,
+			Title:   item.Title,
+			URL:     item.Link,
+			Content: item.Content,
+		})
+	}
+
+	if p.registry != nil {
+		extra, err := p.registry.FetchExtra(blog)
+		if err != nil {
+			return 0, fmt.Errorf("fetch plugins for %q: %w", blog.URL, err)
+		}
+		candidates = append(candidates, extra...)
+	}
+
+	urls := make([]string, len(candidates))
+	for i, a := range candidates {
+		urls[i] = a.URL
+	}
+
+	existing, err := p.db.GetExistingArticleURLs(urls)
+	if err != nil {
+		return 0, fmt.Errorf("check existing articles: %w", err)
+	}
+
+	var toStore []model.Article
+	for _, a := range candidates {
+		if _, ok := existing[a.URL]; ok {
+			continue
+		}
+
+		if p.registry != nil {
+			keep, err := p.registry.FilterArticle(a)
+			if err != nil {
+				return 0, fmt.Errorf("filter plugins for %q: %w", a.URL, err)
+			}
+			if !keep {
+				continue
+			}
+		}
+
+		toStore = append(toStore, a)
+	}
+
+	stored, err := p.db.AddArticlesBulkAndMarkScanned(blog.ID, toStore, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("store articles: %w", err)
+	}
+
+	for _, a := range stored {
+		if p.registry != nil {
+			if err := p.registry.NotifyNew(a); err != nil {
+				return len(stored), fmt.Errorf("notify plugins for %q: %w", a.URL, err)
+			}
+		}
+		if p.OnNewArticle != nil {
+			p.OnNewArticle(a)
+		}
+	}
+
+	return len(stored), nil
+}
+
+// ScanAll scans every watched blog that hasn't been disabled by the
+// health checker and returns the total number of new articles stored.
+func (p *Pipeline) ScanAll() (int, error) {
+	blogs, err := p.db.ListEnabledBlogs()
+	if err != nil {
+		return 0, fmt.Errorf("list blogs: %w", err)
+	}
+
+	total := 0
+	for _, blog := range blogs {
+		n, err := p.ScanBlog(blog)
+		if err != nil {
+			return total, fmt.Errorf("scan blog %q: %w", blog.URL, err)
+		}
+		total += n
+	}
+	return total, nil
+}
@@ -0,0 +1,202 @@
+// Package healthcheck probes watched blogs (and, optionally, their
+// articles) for broken links and dead feeds, recording the results so
+// blogwatcher can disable blogs that have gone away.
+package healthcheck
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Hyaxia/blogwatcher/internal/model"
+	"github.com/Hyaxia/blogwatcher/internal/storage"
+)
+
+const (
+	defaultFailureThreshold  = 5
+	defaultRedirectThreshold = 3
+)
+
+// Checker probes blog URLs for reachability and records the results via
+// db.RecordLinkCheck.
+type Checker struct {
+	db      *storage.DB
+	client  *http.Client
+	workers int
+
+	// FailureThreshold is how many consecutive failed checks a blog
+	// tolerates before it is disabled.
+	FailureThreshold int
+	// FixRedirects updates a blog's URL once a permanent redirect has
+	// been observed RedirectThreshold times in a row.
+	FixRedirects bool
+	// RedirectThreshold is how many consecutive permanent redirects to
+	// the same location trigger updating the blog's URL when
+	// FixRedirects is set.
+	RedirectThreshold int
+	// IncludeArticles also probes every known article URL for each
+	// blog, in addition to the blog's feed URL.
+	IncludeArticles bool
+}
+
+// NewChecker returns a Checker backed by db, running up to workers probes
+// concurrently.
+func NewChecker(db *storage.DB, workers int) *Checker {
+	if workers < 1 {
+		workers = 1
+	}
+	return &Checker{
+		db:      db,
+		workers: workers,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+			// Redirects are reported, not followed, so permanent ones
+			// can be detected and (optionally) acted on.
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		FailureThreshold:  defaultFailureThreshold,
+		RedirectThreshold: defaultRedirectThreshold,
+	}
+}
+
+// Result is the outcome of checking a single blog.
+type Result struct {
+	Blog         model.Blog
+	Check        storage.LinkCheck
+	Disabled     bool
+	RedirectedTo string
+}
+
+// CheckBlogs probes every blog's feed URL (and, if c.IncludeArticles is
+// set, every one of its known articles), disabling blogs whose
+// consecutive failures reach c.FailureThreshold and, if c.FixRedirects is
+// set, following feed URLs that have permanently redirected
+// c.RedirectThreshold times in a row. Results are returned in no
+// particular order.
+func (c *Checker) CheckBlogs(blogs []model.Blog) ([]Result, error) {
+	jobs := make(chan model.Blog)
+	results := make(chan Result, len(blogs))
+	errs := make(chan error, len(blogs))
+
+	var wg sync.WaitGroup
+	for i := 0; i < c.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for blog := range jobs {
+				result, err := c.checkBlog(blog)
+				if err != nil {
+					errs <- err
+					continue
+				}
+				results <- result
+			}
+		}()
+	}
+
+	for _, blog := range blogs {
+		jobs <- blog
+	}
+	close(jobs)
+	wg.Wait()
+	close(results)
+	close(errs)
+
+	for err := range errs {
+		return nil, err
+	}
+
+	out := make([]Result, 0, len(blogs))
+	for r := range results {
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+func (c *Checker) checkBlog(blog model.Blog) (Result, error) {
+	check := probe(c.client, blog.URL)
+	if err := c.db.RecordLinkCheck(check); err != nil {
+		return Result{}, err
+	}
+
+	if c.IncludeArticles {
+		if err := c.checkArticles(blog.ID); err != nil {
+			return Result{}, err
+		}
+	}
+
+	lastHealth, consecutiveFailures, err := c.db.BlogHealth(blog.URL)
+	if err != nil {
+		return Result{}, err
+	}
+	blog.LastHealth = lastHealth
+	blog.ConsecutiveFailures = consecutiveFailures
+
+	result := Result{Blog: blog, Check: check}
+
+	if consecutiveFailures >= c.FailureThreshold && !blog.Disabled {
+		if _, err := c.db.SetBlogDisabled(blog.ID, true); err != nil {
+			return Result{}, err
+		}
+		result.Disabled = true
+	}
+
+	if c.FixRedirects {
+		redirectTo, count, err := c.db.ConsecutivePermanentRedirects(blog.URL)
+		if err != nil {
+			return Result{}, err
+		}
+		if count >= c.RedirectThreshold {
+			if err := c.db.UpdateBlogURL(blog.ID, redirectTo); err != nil {
+				return Result{}, err
+			}
+			result.RedirectedTo = redirectTo
+		}
+	}
+
+	return result, nil
+}
+
+func (c *Checker) checkArticles(blogID int64) error {
+	articles, err := c.db.ListArticles(false, &blogID)
+	if err != nil {
+		return err
+	}
+	for _, a := range articles {
+		if err := c.db.RecordLinkCheck(probe(c.client, a.URL)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// probe issues a HEAD request for url, falling back to GET if the server
+// doesn't support HEAD (405 Method Not Allowed). No conditional headers
+// (If-None-Match, If-Modified-Since) are sent.
+func probe(client *http.Client, url string) storage.LinkCheck {
+	resp, err := doRequest(client, http.MethodHead, url)
+	if err == nil && resp.StatusCode == http.StatusMethodNotAllowed {
+		resp.Body.Close()
+		resp, err = doRequest(client, http.MethodGet, url)
+	}
+	if err != nil {
+		return storage.LinkCheck{URL: url, Error: err.Error()}
+	}
+	defer resp.Body.Close()
+
+	check := storage.LinkCheck{URL: url, Status: resp.StatusCode}
+	if resp.StatusCode >= 300 && resp.StatusCode < 400 {
+		check.RedirectTo = resp.Header.Get("Location")
+	}
+	return check
+}
+
+func doRequest(client *http.Client, method, url string) (*http.Response, error) {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return client.Do(req)
+}
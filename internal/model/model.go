@@ -0,0 +1,31 @@
+// Package model defines the core domain types shared across blogwatcher's
+// storage, fetch, and command layers.
+package model
+
+import "time"
+
+// Blog is a single feed being watched for new articles.
+type Blog struct {
+	ID          int64
+	Name        string
+	URL         string
+	LastScanned time.Time
+	Disabled    bool
+
+	// LastHealth and ConsecutiveFailures are derived from the blog's
+	// link_checks history; the health checker sets them from
+	// DB.BlogHealth's results and they are zero-valued everywhere else.
+	LastHealth          string
+	ConsecutiveFailures int
+}
+
+// Article is a single post discovered on one of the watched blogs.
+type Article struct {
+	ID        int64
+	BlogID    int64
+	Title     string
+	URL       string
+	Content   string
+	IsRead    bool
+	CreatedAt time.Time
+}
@@ -0,0 +1,52 @@
+package plugin
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Config is the parsed contents of plugins.toml: the set of plugins a
+// user has declared, whether built for exec or middleware loading.
+type Config struct {
+	Plugin []Declaration `toml:"plugin"`
+}
+
+// Declaration describes a single plugin entry in plugins.toml.
+type Declaration struct {
+	Name    string `toml:"name"`
+	Kind    Kind   `toml:"kind"`
+	Type    string `toml:"type"` // "exec" or "middleware"
+	Path    string `toml:"path"`
+	Enabled bool   `toml:"enabled"`
+}
+
+// LoadConfig reads and parses plugins.toml at path. A missing file is not
+// an error; it is treated as an empty configuration.
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return cfg, nil
+	}
+
+	if _, err := toml.DecodeFile(path, &cfg); err != nil {
+		return Config{}, fmt.Errorf("decode %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// SaveConfig writes cfg to plugins.toml at path.
+func SaveConfig(path string, cfg Config) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if err := toml.NewEncoder(f).Encode(cfg); err != nil {
+		return fmt.Errorf("encode %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+
+	"github.com/Hyaxia/blogwatcher/internal/model"
+)
+
+// ExecPlugin wraps an external binary speaking blogwatcher's exec plugin
+// protocol: a single JSON request on stdin, a single JSON response on
+// stdout.
+type ExecPlugin struct {
+	name string
+	kind Kind
+	path string
+}
+
+// NewExecPlugin returns an exec plugin that invokes the binary at path
+// for every call of the given kind.
+func NewExecPlugin(name string, kind Kind, path string) *ExecPlugin {
+	return &ExecPlugin{name: name, kind: kind, path: path}
+}
+
+// Name returns the plugin's declared name.
+func (e *ExecPlugin) Name() string { return e.name }
+
+// Init is a no-op for exec plugins: they are stateless, one-shot
+// invocations with no persistent connection to the database.
+func (e *ExecPlugin) Init(PluginDB) error { return nil }
+
+// Close is a no-op for exec plugins.
+func (e *ExecPlugin) Close() error { return nil }
+
+// Filter implements ArticleFilter by invoking the plugin with kind
+// "filter" and returning its reported keep decision. A response that
+// omits keep is treated as keep=true.
+func (e *ExecPlugin) Filter(article model.Article) (bool, error) {
+	resp, err := e.invoke(execRequest{Article: &article})
+	if err != nil {
+		return false, err
+	}
+	if resp.Keep == nil {
+		return true, nil
+	}
+	return *resp.Keep, nil
+}
+
+// Fetch implements BlogFetcher by invoking the plugin with kind "fetch"
+// and returning the articles it reports.
+func (e *ExecPlugin) Fetch(blog model.Blog) ([]model.Article, error) {
+	resp, err := e.invoke(execRequest{Blog: &blog})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Articles, nil
+}
+
+// Notify implements Notifier by invoking the plugin with kind "notify".
+func (e *ExecPlugin) Notify(article model.Article) error {
+	_, err := e.invoke(execRequest{Article: &article})
+	return err
+}
+
+type execRequest struct {
+	Kind    Kind           `json:"kind"`
+	Article *model.Article `json:"article,omitempty"`
+	Blog    *model.Blog    `json:"blog,omitempty"`
+}
+
+type execResponse struct {
+	Articles []model.Article `json:"articles,omitempty"`
+	Keep     *bool           `json:"keep,omitempty"`
+}
+
+func (e *ExecPlugin) invoke(req execRequest) (execResponse, error) {
+	req.Kind = e.kind
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return execResponse{}, fmt.Errorf("encode request for plugin %q: %w", e.name, err)
+	}
+
+	cmd := exec.Command(e.path)
+	cmd.Stdin = bytes.NewReader(payload)
+
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+
+	if err := cmd.Run(); err != nil {
+		return execResponse{}, fmt.Errorf("run plugin %q: %w", e.name, err)
+	}
+
+	var resp execResponse
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		return execResponse{}, fmt.Errorf("decode response from plugin %q: %w", e.name, err)
+	}
+	return resp, nil
+}
@@ -0,0 +1,55 @@
+package plugin
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// configFileName is the declarations file expected inside a plugin
+// directory.
+const configFileName = "plugins.toml"
+
+// LoadRegistry reads plugins.toml from dir and instantiates every
+// enabled plugin declared in it, initializing middleware plugins with
+// db. Exec plugin paths are resolved relative to dir when not absolute.
+func LoadRegistry(dir string, db PluginDB) (*Registry, error) {
+	cfg, err := LoadConfig(filepath.Join(dir, configFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	reg := NewRegistry()
+	for _, decl := range cfg.Plugin {
+		if !decl.Enabled {
+			continue
+		}
+
+		path := decl.Path
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+
+		var p any
+		switch decl.Type {
+		case "exec":
+			p = NewExecPlugin(decl.Name, decl.Kind, path)
+		case "middleware":
+			mw, err := LoadMiddleware(path)
+			if err != nil {
+				return nil, fmt.Errorf("load middleware plugin %q: %w", decl.Name, err)
+			}
+			if err := mw.Init(db); err != nil {
+				return nil, fmt.Errorf("init plugin %q: %w", decl.Name, err)
+			}
+			p = mw
+		default:
+			return nil, fmt.Errorf("plugin %q: unknown type %q", decl.Name, decl.Type)
+		}
+
+		if err := reg.Add(decl.Kind, p); err != nil {
+			return nil, fmt.Errorf("register plugin %q: %w", decl.Name, err)
+		}
+	}
+
+	return reg, nil
+}
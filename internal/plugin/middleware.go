@@ -0,0 +1,32 @@
+package plugin
+
+import (
+	"fmt"
+	goplugin "plugin"
+)
+
+// pluginSymbol is the name every middleware plugin must export: a value
+// implementing Plugin, plus whichever of ArticleFilter, BlogFetcher, or
+// Notifier it provides.
+const pluginSymbol = "Plugin"
+
+// LoadMiddleware opens a Go plugin built with `go build -buildmode=plugin`
+// and returns the Plugin it exports.
+func LoadMiddleware(path string) (Plugin, error) {
+	lib, err := goplugin.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open plugin %s: %w", path, err)
+	}
+
+	sym, err := lib.Lookup(pluginSymbol)
+	if err != nil {
+		return nil, fmt.Errorf("lookup %s symbol in %s: %w", pluginSymbol, path, err)
+	}
+
+	p, ok := sym.(Plugin)
+	if !ok {
+		return nil, fmt.Errorf("%s in %s does not implement plugin.Plugin", pluginSymbol, path)
+	}
+
+	return p, nil
+}
@@ -0,0 +1,154 @@
+// Package plugin lets blogwatcher be extended with custom fetchers,
+// filters, and notifiers without recompiling, either as external
+// executables speaking a small JSON protocol or as Go plugins loaded with
+// plugin.Open.
+package plugin
+
+import (
+	"fmt"
+
+	"github.com/Hyaxia/blogwatcher/internal/model"
+)
+
+// Kind identifies what stage of the scan pipeline a plugin participates
+// in.
+type Kind string
+
+const (
+	KindFetch  Kind = "fetch"
+	KindFilter Kind = "filter"
+	KindNotify Kind = "notify"
+)
+
+// PluginDB is the limited database surface exposed to plugins during
+// Init, so they can look things up without being able to reshape the
+// schema or delete data outright.
+type PluginDB interface {
+	ListBlogs() ([]model.Blog, error)
+	GetArticle(id int64) (*model.Article, error)
+}
+
+// ArticleFilter decides whether a newly fetched article should be kept.
+type ArticleFilter interface {
+	Filter(article model.Article) (keep bool, err error)
+}
+
+// BlogFetcher fetches additional articles for a blog, alongside
+// blogwatcher's built-in feed fetching.
+type BlogFetcher interface {
+	Fetch(blog model.Blog) ([]model.Article, error)
+}
+
+// Notifier is told about every new article once it has been stored.
+type Notifier interface {
+	Notify(article model.Article) error
+}
+
+// Plugin is the lifecycle every middleware plugin must implement,
+// alongside whichever of ArticleFilter, BlogFetcher, or Notifier it
+// provides.
+type Plugin interface {
+	Name() string
+	Init(db PluginDB) error
+	Close() error
+}
+
+// Registry holds the plugins active for a scan pipeline, grouped by the
+// role they play.
+type Registry struct {
+	fetchers  []BlogFetcher
+	filters   []ArticleFilter
+	notifiers []Notifier
+	loaded    []Plugin
+	seen      map[Plugin]bool
+}
+
+// NewRegistry returns an empty plugin registry.
+func NewRegistry() *Registry {
+	return &Registry{seen: make(map[Plugin]bool)}
+}
+
+// Add registers a plugin for the given kind. A plugin implementing
+// multiple roles must be added once per kind it should act as.
+func (r *Registry) Add(kind Kind, p any) error {
+	switch kind {
+	case KindFetch:
+		f, ok := p.(BlogFetcher)
+		if !ok {
+			return fmt.Errorf("plugin does not implement BlogFetcher")
+		}
+		r.fetchers = append(r.fetchers, f)
+	case KindFilter:
+		f, ok := p.(ArticleFilter)
+		if !ok {
+			return fmt.Errorf("plugin does not implement ArticleFilter")
+		}
+		r.filters = append(r.filters, f)
+	case KindNotify:
+		n, ok := p.(Notifier)
+		if !ok {
+			return fmt.Errorf("plugin does not implement Notifier")
+		}
+		r.notifiers = append(r.notifiers, n)
+	default:
+		return fmt.Errorf("unknown plugin kind %q", kind)
+	}
+
+	if pl, ok := p.(Plugin); ok && !r.seen[pl] {
+		r.seen[pl] = true
+		r.loaded = append(r.loaded, pl)
+	}
+	return nil
+}
+
+// FetchExtra asks every registered fetcher plugin for additional articles
+// on top of blogwatcher's own feed fetch.
+func (r *Registry) FetchExtra(blog model.Blog) ([]model.Article, error) {
+	var extra []model.Article
+	for _, f := range r.fetchers {
+		articles, err := f.Fetch(blog)
+		if err != nil {
+			return nil, fmt.Errorf("fetch plugin: %w", err)
+		}
+		extra = append(extra, articles...)
+	}
+	return extra, nil
+}
+
+// FilterArticle runs an article through every registered filter plugin,
+// keeping it only if all of them do.
+func (r *Registry) FilterArticle(article model.Article) (bool, error) {
+	for _, f := range r.filters {
+		keep, err := f.Filter(article)
+		if err != nil {
+			return false, fmt.Errorf("filter plugin: %w", err)
+		}
+		if !keep {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// NotifyNew tells every registered notifier plugin about a newly stored
+// article.
+func (r *Registry) NotifyNew(article model.Article) error {
+	for _, n := range r.notifiers {
+		if err := n.Notify(article); err != nil {
+			return fmt.Errorf("notify plugin: %w", err)
+		}
+	}
+	return nil
+}
+
+// Close shuts down every loaded plugin, returning the first error
+// encountered after attempting to close them all.
+func (r *Registry) Close() error {
+	var firstErr error
+	for _, p := range r.loaded {
+		if err := p.Close(); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("close plugin %q: %w", p.Name(), err)
+		}
+	}
+	return firstErr
+}
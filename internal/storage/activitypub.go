@@ -0,0 +1,214 @@
+package storage
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+const activityPubSchema = `
+CREATE TABLE IF NOT EXISTS activitypub_keys (
+	id INTEGER PRIMARY KEY CHECK (id = 1),
+	private_key BLOB NOT NULL,
+	public_key BLOB NOT NULL,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS activitypub_followers (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	blog_id INTEGER NOT NULL REFERENCES blogs(id) ON DELETE CASCADE,
+	follower_iri TEXT NOT NULL,
+	inbox TEXT NOT NULL,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (blog_id, follower_iri)
+);
+
+CREATE TABLE IF NOT EXISTS activitypub_deliveries (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	follower_id INTEGER NOT NULL REFERENCES activitypub_followers(id) ON DELETE CASCADE,
+	article_id INTEGER NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+	status TEXT NOT NULL DEFAULT 'pending',
+	attempts INTEGER NOT NULL DEFAULT 0,
+	last_error TEXT NOT NULL DEFAULT '',
+	next_attempt_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`
+
+func (db *DB) ensureActivityPubSchema() error {
+	_, err := db.write.Exec(activityPubSchema)
+	return err
+}
+
+// GetOrCreateActorKeypair returns the Ed25519 keypair used to sign this
+// instance's ActivityPub activities, generating and persisting one the
+// first time it is called.
+func (db *DB) GetOrCreateActorKeypair() (ed25519.PrivateKey, ed25519.PublicKey, error) {
+	row := db.write.QueryRow(`SELECT private_key, public_key FROM activitypub_keys WHERE id = 1`)
+
+	var priv, pub []byte
+	err := row.Scan(&priv, &pub)
+	if err == nil {
+		return ed25519.PrivateKey(priv), ed25519.PublicKey(pub), nil
+	}
+	if err != sql.ErrNoRows {
+		return nil, nil, fmt.Errorf("read actor keypair: %w", err)
+	}
+
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("generate actor keypair: %w", err)
+	}
+
+	_, err = db.write.Exec(`INSERT INTO activitypub_keys (id, private_key, public_key) VALUES (1, ?, ?)`,
+		[]byte(privKey), []byte(pubKey))
+	if err != nil {
+		return nil, nil, fmt.Errorf("store actor keypair: %w", err)
+	}
+
+	return privKey, pubKey, nil
+}
+
+// Follower is a remote actor following one of our blogs.
+type Follower struct {
+	ID          int64
+	BlogID      int64
+	FollowerIRI string
+	Inbox       string
+}
+
+// AddFollower records that followerIRI (whose inbox is inbox) follows
+// blogID, replacing any existing follower row for the same pair.
+func (db *DB) AddFollower(blogID int64, followerIRI, inbox string) (Follower, error) {
+	res, err := db.write.Exec(
+		`INSERT INTO activitypub_followers (blog_id, follower_iri, inbox) VALUES (?, ?, ?)
+		 ON CONFLICT (blog_id, follower_iri) DO UPDATE SET inbox = excluded.inbox`,
+		blogID, followerIRI, inbox)
+	if err != nil {
+		return Follower{}, fmt.Errorf("add follower: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return Follower{}, fmt.Errorf("read inserted follower id: %w", err)
+	}
+
+	return Follower{ID: id, BlogID: blogID, FollowerIRI: followerIRI, Inbox: inbox}, nil
+}
+
+// RemoveFollower deletes a follower of blogID, reporting whether a row
+// was actually removed.
+func (db *DB) RemoveFollower(blogID int64, followerIRI string) (bool, error) {
+	res, err := db.write.Exec(
+		`DELETE FROM activitypub_followers WHERE blog_id = ? AND follower_iri = ?`, blogID, followerIRI)
+	if err != nil {
+		return false, fmt.Errorf("remove follower: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("read rows affected: %w", err)
+	}
+	return n > 0, nil
+}
+
+// ListFollowers returns every follower of blogID.
+func (db *DB) ListFollowers(blogID int64) ([]Follower, error) {
+	rows, err := db.read.Query(
+		`SELECT id, blog_id, follower_iri, inbox FROM activitypub_followers WHERE blog_id = ?`, blogID)
+	if err != nil {
+		return nil, fmt.Errorf("list followers: %w", err)
+	}
+	defer rows.Close()
+
+	var followers []Follower
+	for rows.Next() {
+		var f Follower
+		if err := rows.Scan(&f.ID, &f.BlogID, &f.FollowerIRI, &f.Inbox); err != nil {
+			return nil, fmt.Errorf("scan follower: %w", err)
+		}
+		followers = append(followers, f)
+	}
+	return followers, rows.Err()
+}
+
+// DeliveryStatus is the outcome of a single attempt to deliver an
+// activity to a follower's inbox.
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	DeliveryFailed    DeliveryStatus = "failed"
+	DeliveryDead      DeliveryStatus = "dead"
+)
+
+// Delivery is one queued or attempted activity delivery to a follower's
+// inbox.
+type Delivery struct {
+	ID            int64
+	FollowerID    int64
+	ArticleID     int64
+	Status        DeliveryStatus
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+}
+
+// EnqueueDelivery queues an activity announcing articleID for delivery
+// to followerID's inbox.
+func (db *DB) EnqueueDelivery(followerID, articleID int64) (Delivery, error) {
+	res, err := db.write.Exec(
+		`INSERT INTO activitypub_deliveries (follower_id, article_id) VALUES (?, ?)`,
+		followerID, articleID)
+	if err != nil {
+		return Delivery{}, fmt.Errorf("enqueue delivery: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return Delivery{}, fmt.Errorf("read inserted delivery id: %w", err)
+	}
+
+	return Delivery{ID: id, FollowerID: followerID, ArticleID: articleID, Status: DeliveryPending}, nil
+}
+
+// ListDueDeliveries returns pending or failed deliveries whose next
+// attempt is due.
+func (db *DB) ListDueDeliveries(now time.Time) ([]Delivery, error) {
+	rows, err := db.read.Query(
+		`SELECT id, follower_id, article_id, status, attempts, last_error, next_attempt_at
+		 FROM activitypub_deliveries
+		 WHERE status IN ('pending', 'failed') AND next_attempt_at <= ?`, now)
+	if err != nil {
+		return nil, fmt.Errorf("list due deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []Delivery
+	for rows.Next() {
+		var d Delivery
+		if err := rows.Scan(&d.ID, &d.FollowerID, &d.ArticleID, &d.Status, &d.Attempts, &d.LastError, &d.NextAttemptAt); err != nil {
+			return nil, fmt.Errorf("scan delivery: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// RecordDeliveryAttempt updates a delivery after an attempt, storing the
+// status, attempt count, and last error the caller passes and scheduling
+// the next attempt for nextAttemptAt. Which status follows a failure
+// ("failed" or "dead") is decided by the caller.
+func (db *DB) RecordDeliveryAttempt(id int64, status DeliveryStatus, attempts int, lastErr string, nextAttemptAt time.Time) error {
+	_, err := db.write.Exec(
+		`UPDATE activitypub_deliveries
+		 SET status = ?, attempts = ?, last_error = ?, next_attempt_at = ?
+		 WHERE id = ?`,
+		status, attempts, lastErr, nextAttemptAt, id)
+	if err != nil {
+		return fmt.Errorf("record delivery attempt: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,492 @@
+// Package storage provides the SQLite-backed persistence layer for
+// blogwatcher: blogs, their articles, and the full-text search index used
+// to query them.
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Hyaxia/blogwatcher/internal/model"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS blogs (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL,
+	url TEXT NOT NULL UNIQUE,
+	last_scanned DATETIME
+);
+
+CREATE TABLE IF NOT EXISTS articles (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	blog_id INTEGER NOT NULL REFERENCES blogs(id) ON DELETE CASCADE,
+	title TEXT NOT NULL,
+	url TEXT NOT NULL UNIQUE,
+	content TEXT NOT NULL DEFAULT '',
+	is_read INTEGER NOT NULL DEFAULT 0,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`
+
+// sqliteOpts are appended to every connection's DSN: WAL lets readers and
+// the single writer operate concurrently, and a generous busy timeout
+// lets the writer queue up behind another connection's lock rather than
+// fail with SQLITE_BUSY.
+const sqliteOpts = "_journal=WAL&_busy_timeout=5000&_synchronous=NORMAL"
+
+// readPoolSize caps concurrent read connections; SQLite serializes
+// writers regardless, so there's no benefit to a larger write pool.
+const readPoolSize = 8
+
+// DB wraps the SQLite connections used to store blogs and articles. write
+// is a single connection (SQLite has one writer at a time); read is a
+// pooled, read-only connection used by every query that isn't part of a
+// write path or an explicit transaction.
+type DB struct {
+	write *sql.DB
+	read  *sql.DB
+
+	// searchDisabled is set when the linked SQLite library lacks the FTS5
+	// module (the default build tags for go-sqlite3 omit it), so
+	// SearchArticles can fail clearly instead of OpenDatabase erroring out
+	// for every command that never searches.
+	searchDisabled bool
+}
+
+// OpenDatabase opens (creating if necessary) the SQLite database at path
+// and ensures the schema is up to date.
+func OpenDatabase(path string) (*DB, error) {
+	write, err := sql.Open(driverName, path+"?"+sqliteOpts)
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite3 write connection: %w", err)
+	}
+	write.SetMaxOpenConns(1)
+
+	read, err := sql.Open(driverName, path+"?"+sqliteOpts+"&mode=ro")
+	if err != nil {
+		write.Close()
+		return nil, fmt.Errorf("open sqlite3 read connection: %w", err)
+	}
+	read.SetMaxOpenConns(readPoolSize)
+
+	db := &DB{write: write, read: read}
+
+	if _, err := write.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("apply schema: %w", err)
+	}
+	if err := db.ensureSearchIndex(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ensure search index: %w", err)
+	}
+	if err := db.ensureActivityPubSchema(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ensure activitypub schema: %w", err)
+	}
+	if err := db.ensureHealthCheckSchema(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ensure health check schema: %w", err)
+	}
+	if err := db.ensureScanLocksSchema(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ensure scan locks schema: %w", err)
+	}
+
+	return db, nil
+}
+
+// Close releases the underlying database connections.
+func (db *DB) Close() error {
+	readErr := db.read.Close()
+	writeErr := db.write.Close()
+	if writeErr != nil {
+		return writeErr
+	}
+	return readErr
+}
+
+// Tx is a write transaction passed to a WithTx callback. Its methods
+// mirror the DB methods that participate in multi-step atomic
+// operations.
+type Tx struct {
+	tx *sql.Tx
+}
+
+// WithTx runs fn inside a write transaction, committing if fn returns
+// nil and rolling back otherwise.
+func (db *DB) WithTx(fn func(*Tx) error) error {
+	tx, err := db.write.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(&Tx{tx: tx}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// AddBlog inserts a new blog and returns it with its assigned ID.
+func (db *DB) AddBlog(blog model.Blog) (model.Blog, error) {
+	res, err := db.write.Exec(`INSERT INTO blogs (name, url, last_scanned) VALUES (?, ?, ?)`,
+		blog.Name, blog.URL, nullTime(blog.LastScanned))
+	if err != nil {
+		return model.Blog{}, fmt.Errorf("insert blog: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return model.Blog{}, fmt.Errorf("read inserted blog id: %w", err)
+	}
+	blog.ID = id
+
+	return blog, nil
+}
+
+// GetBlog returns the blog with the given ID, or nil if it does not exist.
+func (db *DB) GetBlog(id int64) (*model.Blog, error) {
+	row := db.read.QueryRow(`SELECT id, name, url, last_scanned, disabled FROM blogs WHERE id = ?`, id)
+	return scanBlog(row)
+}
+
+// ListBlogs returns every watched blog.
+func (db *DB) ListBlogs() ([]model.Blog, error) {
+	rows, err := db.read.Query(`SELECT id, name, url, last_scanned, disabled FROM blogs ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("list blogs: %w", err)
+	}
+	defer rows.Close()
+
+	var blogs []model.Blog
+	for rows.Next() {
+		var b model.Blog
+		var lastScanned sql.NullTime
+		var disabled int
+		if err := rows.Scan(&b.ID, &b.Name, &b.URL, &lastScanned, &disabled); err != nil {
+			return nil, fmt.Errorf("scan blog: %w", err)
+		}
+		if lastScanned.Valid {
+			b.LastScanned = lastScanned.Time
+		}
+		b.Disabled = disabled != 0
+		blogs = append(blogs, b)
+	}
+	return blogs, rows.Err()
+}
+
+// ListEnabledBlogs returns every watched blog that has not been disabled
+// by the health checker, for use by commands that should skip dead blogs.
+func (db *DB) ListEnabledBlogs() ([]model.Blog, error) {
+	blogs, err := db.ListBlogs()
+	if err != nil {
+		return nil, err
+	}
+	enabled := make([]model.Blog, 0, len(blogs))
+	for _, b := range blogs {
+		if !b.Disabled {
+			enabled = append(enabled, b)
+		}
+	}
+	return enabled, nil
+}
+
+// SetBlogDisabled marks a blog as disabled or re-enables it, reporting
+// whether a row was actually updated.
+func (db *DB) SetBlogDisabled(id int64, disabled bool) (bool, error) {
+	res, err := db.write.Exec(`UPDATE blogs SET disabled = ? WHERE id = ?`, boolToInt(disabled), id)
+	if err != nil {
+		return false, fmt.Errorf("set blog disabled: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("read rows affected: %w", err)
+	}
+	return n > 0, nil
+}
+
+// UpdateBlogURL updates a blog's feed URL, used when the health checker
+// follows a permanent redirect to its new address.
+func (db *DB) UpdateBlogURL(id int64, url string) error {
+	_, err := db.write.Exec(`UPDATE blogs SET url = ? WHERE id = ?`, url, id)
+	if err != nil {
+		return fmt.Errorf("update blog url: %w", err)
+	}
+	return nil
+}
+
+// RemoveBlog deletes the blog with the given ID, reporting whether a row
+// was actually removed.
+func (db *DB) RemoveBlog(id int64) (bool, error) {
+	res, err := db.write.Exec(`DELETE FROM blogs WHERE id = ?`, id)
+	if err != nil {
+		return false, fmt.Errorf("delete blog: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("read rows affected: %w", err)
+	}
+	return n > 0, nil
+}
+
+// UpdateBlogLastScanned records the time a blog was last scanned for new
+// articles.
+func (db *DB) UpdateBlogLastScanned(id int64, when time.Time) error {
+	_, err := db.write.Exec(`UPDATE blogs SET last_scanned = ? WHERE id = ?`, when, id)
+	if err != nil {
+		return fmt.Errorf("update last scanned: %w", err)
+	}
+	return nil
+}
+
+// UpdateBlogLastScanned records the time a blog was last scanned for new
+// articles, as part of tx.
+func (t *Tx) UpdateBlogLastScanned(id int64, when time.Time) error {
+	_, err := t.tx.Exec(`UPDATE blogs SET last_scanned = ? WHERE id = ?`, when, id)
+	if err != nil {
+		return fmt.Errorf("update last scanned: %w", err)
+	}
+	return nil
+}
+
+// AddArticle inserts a single article and returns it with its assigned ID
+// and created_at timestamp.
+func (db *DB) AddArticle(article model.Article) (model.Article, error) {
+	now := time.Now()
+	res, err := db.write.Exec(
+		`INSERT INTO articles (blog_id, title, url, content, is_read, created_at) VALUES (?, ?, ?, ?, ?, ?)`,
+		article.BlogID, article.Title, article.URL, article.Content, boolToInt(article.IsRead), now)
+	if err != nil {
+		return model.Article{}, fmt.Errorf("insert article: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return model.Article{}, fmt.Errorf("read inserted article id: %w", err)
+	}
+	article.ID = id
+	article.CreatedAt = now
+
+	return article, nil
+}
+
+// AddArticlesBulk inserts multiple articles in a single transaction and
+// returns them with their assigned IDs.
+func (db *DB) AddArticlesBulk(articles []model.Article) ([]model.Article, error) {
+	var stored []model.Article
+	err := db.WithTx(func(tx *Tx) error {
+		s, err := tx.AddArticlesBulk(articles)
+		stored = s
+		return err
+	})
+	return stored, err
+}
+
+// AddArticlesBulk inserts multiple articles as part of tx and returns them
+// with their assigned IDs and created_at timestamps.
+func (t *Tx) AddArticlesBulk(articles []model.Article) ([]model.Article, error) {
+	if len(articles) == 0 {
+		return nil, nil
+	}
+
+	stmt, err := t.tx.Prepare(
+		`INSERT INTO articles (blog_id, title, url, content, is_read, created_at) VALUES (?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return nil, fmt.Errorf("prepare insert: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now()
+	stored := make([]model.Article, 0, len(articles))
+	for _, article := range articles {
+		res, err := stmt.Exec(article.BlogID, article.Title, article.URL, article.Content, boolToInt(article.IsRead), now)
+		if err != nil {
+			return nil, fmt.Errorf("insert article %q: %w", article.URL, err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return nil, fmt.Errorf("read inserted article id for %q: %w", article.URL, err)
+		}
+		article.ID = id
+		article.CreatedAt = now
+		stored = append(stored, article)
+	}
+
+	return stored, nil
+}
+
+// AddArticlesBulkAndMarkScanned stores articles and records blogID's scan
+// time atomically, so a crash between the two can never leave new
+// articles stored under a stale last_scanned time (or vice versa). It
+// returns the stored articles with their assigned IDs.
+func (db *DB) AddArticlesBulkAndMarkScanned(blogID int64, articles []model.Article, when time.Time) ([]model.Article, error) {
+	var stored []model.Article
+	err := db.WithTx(func(tx *Tx) error {
+		s, err := tx.AddArticlesBulk(articles)
+		if err != nil {
+			return err
+		}
+		stored = s
+		return tx.UpdateBlogLastScanned(blogID, when)
+	})
+	return stored, err
+}
+
+// ListArticles returns articles, optionally restricted to unread ones
+// and/or a single blog.
+func (db *DB) ListArticles(unreadOnly bool, blogID *int64) ([]model.Article, error) {
+	query := `SELECT id, blog_id, title, url, content, is_read, created_at FROM articles WHERE 1 = 1`
+	var args []any
+
+	if unreadOnly {
+		query += ` AND is_read = 0`
+	}
+	if blogID != nil {
+		query += ` AND blog_id = ?`
+		args = append(args, *blogID)
+	}
+	query += ` ORDER BY created_at DESC`
+
+	rows, err := db.read.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list articles: %w", err)
+	}
+	defer rows.Close()
+
+	var articles []model.Article
+	for rows.Next() {
+		a, err := scanArticleRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		articles = append(articles, a)
+	}
+	return articles, rows.Err()
+}
+
+// GetArticle returns the article with the given ID, or nil if it does not
+// exist.
+func (db *DB) GetArticle(id int64) (*model.Article, error) {
+	row := db.read.QueryRow(
+		`SELECT id, blog_id, title, url, content, is_read, created_at FROM articles WHERE id = ?`, id)
+	return scanArticle(row)
+}
+
+// MarkArticleRead marks the article with the given ID as read, reporting
+// whether a row was actually updated.
+func (db *DB) MarkArticleRead(id int64) (bool, error) {
+	res, err := db.write.Exec(`UPDATE articles SET is_read = 1 WHERE id = ?`, id)
+	if err != nil {
+		return false, fmt.Errorf("mark article read: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("read rows affected: %w", err)
+	}
+	return n > 0, nil
+}
+
+// GetExistingArticleURLs returns the subset of the given URLs that are
+// already present in the database, so callers can skip re-inserting them.
+func (db *DB) GetExistingArticleURLs(urls []string) (map[string]struct{}, error) {
+	existing := make(map[string]struct{})
+	if len(urls) == 0 {
+		return existing, nil
+	}
+
+	placeholders := make([]string, len(urls))
+	args := make([]any, len(urls))
+	for i, u := range urls {
+		placeholders[i] = "?"
+		args[i] = u
+	}
+
+	query := fmt.Sprintf(`SELECT url FROM articles WHERE url IN (%s)`, joinPlaceholders(placeholders))
+	rows, err := db.read.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query existing urls: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var u string
+		if err := rows.Scan(&u); err != nil {
+			return nil, fmt.Errorf("scan existing url: %w", err)
+		}
+		existing[u] = struct{}{}
+	}
+	return existing, rows.Err()
+}
+
+func scanBlog(row *sql.Row) (*model.Blog, error) {
+	var b model.Blog
+	var lastScanned sql.NullTime
+	var disabled int
+	err := row.Scan(&b.ID, &b.Name, &b.URL, &lastScanned, &disabled)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scan blog: %w", err)
+	}
+	if lastScanned.Valid {
+		b.LastScanned = lastScanned.Time
+	}
+	b.Disabled = disabled != 0
+	return &b, nil
+}
+
+func scanArticle(row *sql.Row) (*model.Article, error) {
+	var a model.Article
+	var isRead int
+	err := row.Scan(&a.ID, &a.BlogID, &a.Title, &a.URL, &a.Content, &isRead, &a.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scan article: %w", err)
+	}
+	a.IsRead = isRead != 0
+	return &a, nil
+}
+
+func scanArticleRow(rows *sql.Rows) (model.Article, error) {
+	var a model.Article
+	var isRead int
+	if err := rows.Scan(&a.ID, &a.BlogID, &a.Title, &a.URL, &a.Content, &isRead, &a.CreatedAt); err != nil {
+		return model.Article{}, fmt.Errorf("scan article: %w", err)
+	}
+	a.IsRead = isRead != 0
+	return a, nil
+}
+
+func nullTime(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func joinPlaceholders(placeholders []string) string {
+	out := ""
+	for i, p := range placeholders {
+		if i > 0 {
+			out += ", "
+		}
+		out += p
+	}
+	return out
+}
@@ -1,8 +1,10 @@
 package storage
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
@@ -42,12 +44,17 @@ func TestDatabaseCreatesFileAndCRUD(t *testing.T) {
 		{BlogID: blog.ID, Title: "One", URL: "https://example.com/1"},
 		{BlogID: blog.ID, Title: "Two", URL: "https://example.com/2"},
 	}
-	count, err := db.AddArticlesBulk(articles)
+	stored, err := db.AddArticlesBulk(articles)
 	if err != nil {
 		t.Fatalf("add articles bulk: %v", err)
 	}
-	if count != 2 {
-		t.Fatalf("expected 2 articles, got %d", count)
+	if len(stored) != 2 {
+		t.Fatalf("expected 2 articles, got %d", len(stored))
+	}
+	for _, a := range stored {
+		if a.ID == 0 {
+			t.Fatalf("expected assigned article ID, got %+v", a)
+		}
 	}
 
 	list, err := db.ListArticles(false, nil)
@@ -85,6 +92,68 @@ func TestDatabaseCreatesFileAndCRUD(t *testing.T) {
 	}
 }
 
+func TestConcurrentAddArticlesBulkAndMarkArticleRead(t *testing.T) {
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "blogwatcher.db")
+	db, err := OpenDatabase(path)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	defer db.Close()
+
+	blog, err := db.AddBlog(model.Blog{Name: "Test", URL: "https://example.com"})
+	if err != nil {
+		t.Fatalf("add blog: %v", err)
+	}
+
+	const goroutines = 20
+
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			articles := []model.Article{
+				{BlogID: blog.ID, Title: "Post", URL: fmt.Sprintf("https://example.com/%d", i)},
+			}
+			if _, err := db.AddArticlesBulk(articles); err != nil {
+				t.Errorf("add articles bulk: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	list, err := db.ListArticles(false, &blog.ID)
+	if err != nil {
+		t.Fatalf("list articles: %v", err)
+	}
+	if len(list) != goroutines {
+		t.Fatalf("expected %d articles, got %d", goroutines, len(list))
+	}
+
+	wg = sync.WaitGroup{}
+	for _, a := range list {
+		wg.Add(1)
+		go func(id int64) {
+			defer wg.Done()
+			if _, err := db.MarkArticleRead(id); err != nil {
+				t.Errorf("mark article read: %v", err)
+			}
+		}(a.ID)
+	}
+	wg.Wait()
+
+	updated, err := db.ListArticles(false, &blog.ID)
+	if err != nil {
+		t.Fatalf("list articles: %v", err)
+	}
+	for _, a := range updated {
+		if !a.IsRead {
+			t.Fatalf("expected article %d to be read", a.ID)
+		}
+	}
+}
+
 func TestGetExistingArticleURLs(t *testing.T) {
 	tmp := t.TempDir()
 	path := filepath.Join(tmp, "blogwatcher.db")
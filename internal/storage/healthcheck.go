@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const linkChecksSchema = `
+CREATE TABLE IF NOT EXISTS link_checks (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	url TEXT NOT NULL,
+	status INTEGER NOT NULL,
+	checked_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	redirect_to TEXT NOT NULL DEFAULT '',
+	error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_link_checks_url_checked_at ON link_checks (url, checked_at);
+`
+
+// ensureHealthCheckSchema creates the link_checks table and adds the
+// blogs.disabled column used by the health checker. SQLite has no
+// "ADD COLUMN IF NOT EXISTS", so the ALTER TABLE's "duplicate column
+// name" error is swallowed on every OpenDatabase call after the first.
+func (db *DB) ensureHealthCheckSchema() error {
+	if _, err := db.write.Exec(linkChecksSchema); err != nil {
+		return err
+	}
+
+	_, err := db.write.Exec(`ALTER TABLE blogs ADD COLUMN disabled INTEGER NOT NULL DEFAULT 0`)
+	if err != nil && !strings.Contains(err.Error(), "duplicate column name") {
+		return err
+	}
+	return nil
+}
+
+// LinkCheck is the result of a single HEAD/GET probe of a URL.
+type LinkCheck struct {
+	URL        string
+	Status     int
+	CheckedAt  time.Time
+	RedirectTo string
+	Error      string
+}
+
+// RecordLinkCheck stores the outcome of probing a URL.
+func (db *DB) RecordLinkCheck(check LinkCheck) error {
+	checkedAt := check.CheckedAt
+	if checkedAt.IsZero() {
+		checkedAt = time.Now()
+	}
+	_, err := db.write.Exec(
+		`INSERT INTO link_checks (url, status, checked_at, redirect_to, error) VALUES (?, ?, ?, ?, ?)`,
+		check.URL, check.Status, checkedAt, check.RedirectTo, check.Error)
+	if err != nil {
+		return fmt.Errorf("record link check: %w", err)
+	}
+	return nil
+}
+
+// BlogHealth reports a blog's most recent check status and how many of
+// its most recent checks, counting back from and including the latest,
+// failed in a row (a non-2xx status or a transport error).
+func (db *DB) BlogHealth(url string) (lastHealth string, consecutiveFailures int, err error) {
+	rows, err := db.read.Query(
+		`SELECT status, error FROM link_checks WHERE url = ? ORDER BY checked_at DESC`, url)
+	if err != nil {
+		return "", 0, fmt.Errorf("query link checks: %w", err)
+	}
+	defer rows.Close()
+
+	first := true
+	for rows.Next() {
+		var status int
+		var checkErr string
+		if err := rows.Scan(&status, &checkErr); err != nil {
+			return "", 0, fmt.Errorf("scan link check: %w", err)
+		}
+
+		failed := checkErr != "" || status < 200 || status >= 300
+		if first {
+			if failed {
+				lastHealth = "failing"
+			} else {
+				lastHealth = "ok"
+			}
+			first = false
+		}
+		if !failed {
+			break
+		}
+		consecutiveFailures++
+	}
+	if err := rows.Err(); err != nil {
+		return "", 0, err
+	}
+	if first {
+		return "unchecked", 0, nil
+	}
+	return lastHealth, consecutiveFailures, nil
+}
+
+// ConsecutivePermanentRedirects reports how many of the most recent
+// checks for url ended in a permanent redirect (301/308) to the same
+// target, so callers can decide when to follow it.
+func (db *DB) ConsecutivePermanentRedirects(url string) (redirectTo string, count int, err error) {
+	rows, err := db.read.Query(
+		`SELECT status, redirect_to FROM link_checks WHERE url = ? ORDER BY checked_at DESC`, url)
+	if err != nil {
+		return "", 0, fmt.Errorf("query link checks: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var status int
+		var target string
+		if err := rows.Scan(&status, &target); err != nil {
+			return "", 0, fmt.Errorf("scan link check: %w", err)
+		}
+		if (status != 301 && status != 308) || target == "" {
+			break
+		}
+		if redirectTo == "" {
+			redirectTo = target
+		} else if target != redirectTo {
+			break
+		}
+		count++
+	}
+	return redirectTo, count, rows.Err()
+}
@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const scanLocksSchema = `
+CREATE TABLE IF NOT EXISTS scan_locks (
+	blog_id INTEGER PRIMARY KEY REFERENCES blogs(id) ON DELETE CASCADE,
+	holder TEXT NOT NULL,
+	acquired_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`
+
+func (db *DB) ensureScanLocksSchema() error {
+	_, err := db.write.Exec(scanLocksSchema)
+	return err
+}
+
+// ScanCoordinator ensures at most one scan of a given blog runs at a
+// time: a per-blog sync.Mutex guards against concurrent goroutines in
+// this process, and an advisory row in scan_locks guards against
+// multiple blogwatcher processes racing the same database.
+type ScanCoordinator struct {
+	db     *DB
+	holder string
+
+	mu    sync.Mutex
+	locks map[int64]*sync.Mutex
+}
+
+// NewScanCoordinator returns a ScanCoordinator backed by db. holder
+// identifies this process in the scan_locks table, useful for diagnosing
+// a stuck lock.
+func NewScanCoordinator(db *DB, holder string) *ScanCoordinator {
+	return &ScanCoordinator{db: db, holder: holder, locks: make(map[int64]*sync.Mutex)}
+}
+
+func (sc *ScanCoordinator) blogLock(blogID int64) *sync.Mutex {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	lock, ok := sc.locks[blogID]
+	if !ok {
+		lock = &sync.Mutex{}
+		sc.locks[blogID] = lock
+	}
+	return lock
+}
+
+// TryAcquire attempts to claim blogID for a scan. If another goroutine in
+// this process, or another process sharing the database, already holds
+// it, ok is false and release is nil. On success, the caller must call
+// release when the scan finishes.
+func (sc *ScanCoordinator) TryAcquire(blogID int64) (release func(), ok bool, err error) {
+	lock := sc.blogLock(blogID)
+	if !lock.TryLock() {
+		return nil, false, nil
+	}
+
+	_, err = sc.db.write.Exec(
+		`INSERT INTO scan_locks (blog_id, holder, acquired_at) VALUES (?, ?, ?)`,
+		blogID, sc.holder, time.Now())
+	if err != nil {
+		lock.Unlock()
+		if isUniqueConstraintErr(err) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("acquire scan lock for blog %d: %w", blogID, err)
+	}
+
+	release = func() {
+		sc.db.write.Exec(`DELETE FROM scan_locks WHERE blog_id = ?`, blogID)
+		lock.Unlock()
+	}
+	return release, true, nil
+}
+
+func isUniqueConstraintErr(err error) bool {
+	return strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/Hyaxia/blogwatcher/internal/model"
+)
+
+func TestScanCoordinatorTryAcquire(t *testing.T) {
+	tmp := t.TempDir()
+	db, err := OpenDatabase(filepath.Join(tmp, "blogwatcher.db"))
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	defer db.Close()
+
+	blog, err := db.AddBlog(model.Blog{Name: "Test", URL: "https://example.com"})
+	if err != nil {
+		t.Fatalf("add blog: %v", err)
+	}
+
+	coordinator := NewScanCoordinator(db, "test")
+
+	release, ok, err := coordinator.TryAcquire(blog.ID)
+	if err != nil {
+		t.Fatalf("try acquire: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected to acquire lock")
+	}
+
+	if _, ok, err := coordinator.TryAcquire(blog.ID); err != nil {
+		t.Fatalf("try acquire while held: %v", err)
+	} else if ok {
+		t.Fatal("expected lock to already be held")
+	}
+
+	release()
+
+	release2, ok, err := coordinator.TryAcquire(blog.ID)
+	if err != nil {
+		t.Fatalf("try acquire after release: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected to reacquire lock after release")
+	}
+	release2()
+}
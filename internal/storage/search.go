@@ -0,0 +1,184 @@
+// Full-text search requires go-sqlite3 to be built with FTS5 support:
+// build (and test) this package with `-tags sqlite_fts5`.
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	sqlite3 "github.com/mattn/go-sqlite3"
+
+	"github.com/Hyaxia/blogwatcher/internal/model"
+)
+
+// errNoFTS5 is the sqlite3 error text returned when the linked SQLite
+// library was built without the FTS5 extension.
+const errNoFTS5 = "no such module: fts5"
+
+// driverName is the sqlite3 driver registered with the Go functions used by
+// blog stats (wordcount, readtime).
+const driverName = "sqlite3_blogwatcher"
+
+func init() {
+	sql.Register(driverName, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			if err := conn.RegisterFunc("wordcount", wordCount, true); err != nil {
+				return fmt.Errorf("register wordcount: %w", err)
+			}
+			if err := conn.RegisterFunc("readtime", readTime, true); err != nil {
+				return fmt.Errorf("register readtime: %w", err)
+			}
+			return nil
+		},
+	})
+}
+
+const searchSchema = `
+CREATE VIRTUAL TABLE IF NOT EXISTS articles_fts USING fts5(
+	title, content, content='articles', content_rowid='id'
+);
+
+CREATE TRIGGER IF NOT EXISTS articles_fts_ai AFTER INSERT ON articles BEGIN
+	INSERT INTO articles_fts(rowid, title, content) VALUES (new.id, new.title, new.content);
+END;
+
+CREATE TRIGGER IF NOT EXISTS articles_fts_ad AFTER DELETE ON articles BEGIN
+	INSERT INTO articles_fts(articles_fts, rowid, title, content) VALUES ('delete', old.id, old.title, old.content);
+END;
+
+CREATE TRIGGER IF NOT EXISTS articles_fts_au AFTER UPDATE ON articles BEGIN
+	INSERT INTO articles_fts(articles_fts, rowid, title, content) VALUES ('delete', old.id, old.title, old.content);
+	INSERT INTO articles_fts(rowid, title, content) VALUES (new.id, new.title, new.content);
+END;
+`
+
+// ensureSearchIndex creates the FTS5 virtual table and its sync triggers.
+// go-sqlite3 only compiles in the FTS5 module when built with
+// `-tags sqlite_fts5` (see the Makefile's `build-full`/`test-full`
+// targets); a plain `go build`/`go test` links a binary that lacks it.
+// Rather than fail OpenDatabase for every command, search is disabled and
+// SearchArticles reports a clear error instead.
+func (db *DB) ensureSearchIndex() error {
+	_, err := db.write.Exec(searchSchema)
+	if err == nil {
+		return nil
+	}
+	if sqliteErr, ok := err.(sqlite3.Error); ok && strings.Contains(sqliteErr.Error(), errNoFTS5) {
+		db.searchDisabled = true
+		return nil
+	}
+	return err
+}
+
+// SearchOptions narrows a SearchArticles query.
+type SearchOptions struct {
+	// BlogID, if non-nil, restricts results to a single blog.
+	BlogID *int64
+	// Limit caps the number of results returned. Zero means no limit.
+	Limit int
+}
+
+// RankedArticle is an article matched by SearchArticles, along with its
+// bm25 relevance rank and a highlighted snippet of the matching content.
+type RankedArticle struct {
+	model.Article
+	Rank    float64
+	Snippet string
+}
+
+// SearchArticles runs a full-text search over article titles and content
+// using FTS5, ranking results with bm25 and returning a highlighted
+// snippet for each match.
+func (db *DB) SearchArticles(query string, opts SearchOptions) ([]RankedArticle, error) {
+	if db.searchDisabled {
+		return nil, fmt.Errorf("full-text search unavailable: rebuild with -tags sqlite_fts5")
+	}
+
+	sqlQuery := strings.Builder{}
+	sqlQuery.WriteString(`
+		SELECT a.id, a.blog_id, a.title, a.url, a.content, a.is_read, a.created_at,
+		       bm25(articles_fts) AS rank,
+		       snippet(articles_fts, 1, '<b>', '</b>', '...', 10) AS snippet
+		FROM articles_fts
+		JOIN articles a ON a.id = articles_fts.rowid
+		WHERE articles_fts MATCH ?`)
+
+	args := []any{query}
+
+	if opts.BlogID != nil {
+		sqlQuery.WriteString(` AND a.blog_id = ?`)
+		args = append(args, *opts.BlogID)
+	}
+
+	sqlQuery.WriteString(` ORDER BY rank`)
+
+	if opts.Limit > 0 {
+		sqlQuery.WriteString(` LIMIT ?`)
+		args = append(args, opts.Limit)
+	}
+
+	rows, err := db.read.Query(sqlQuery.String(), args...)
+	if err != nil {
+		return nil, fmt.Errorf("search articles: %w", err)
+	}
+	defer rows.Close()
+
+	var results []RankedArticle
+	for rows.Next() {
+		var r RankedArticle
+		var isRead int
+		if err := rows.Scan(&r.ID, &r.BlogID, &r.Title, &r.URL, &r.Content, &isRead, &r.CreatedAt, &r.Rank, &r.Snippet); err != nil {
+			return nil, fmt.Errorf("scan search result: %w", err)
+		}
+		r.IsRead = isRead != 0
+		results = append(results, r)
+	}
+	return results, rows.Err()
+}
+
+// BlogStats summarizes the articles collected for a single blog.
+type BlogStats struct {
+	PostCount       int
+	TotalWords      int
+	AverageReadTime float64
+}
+
+// GetBlogStats returns the post count, total word count, and average
+// estimated read time (in minutes) for a blog's articles.
+func (db *DB) GetBlogStats(blogID int64) (BlogStats, error) {
+	var stats BlogStats
+	row := db.read.QueryRow(`
+		SELECT COUNT(*), COALESCE(SUM(wordcount(content)), 0), COALESCE(AVG(readtime(content)), 0)
+		FROM articles
+		WHERE blog_id = ?`, blogID)
+
+	if err := row.Scan(&stats.PostCount, &stats.TotalWords, &stats.AverageReadTime); err != nil {
+		return BlogStats{}, fmt.Errorf("get blog stats: %w", err)
+	}
+	return stats, nil
+}
+
+// wordCount returns an approximate word count for text, splitting on
+// whitespace.
+func wordCount(text string) int64 {
+	return int64(len(strings.Fields(text)))
+}
+
+// averageWordsPerMinute is the reading speed used to estimate read times.
+const averageWordsPerMinute = 200
+
+// readTime estimates the minutes needed to read text at
+// averageWordsPerMinute, rounding up and never returning less than one
+// minute for non-empty text.
+func readTime(text string) int64 {
+	words := wordCount(text)
+	if words == 0 {
+		return 0
+	}
+	minutes := (words + averageWordsPerMinute - 1) / averageWordsPerMinute
+	if minutes < 1 {
+		minutes = 1
+	}
+	return minutes
+}